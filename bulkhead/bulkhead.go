@@ -0,0 +1,115 @@
+package bulkhead
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/metric"
+)
+
+//Bulkhead a semaphore which limits the concurrent requests, so that one slow downstream does not use up
+//all the goroutines of the service. The requests beyond the limit wait in a bounded queue.
+type Bulkhead struct {
+	name     string
+	permits  chan struct{}
+	maxQueue int64
+	maxWait  time.Duration
+	waiting  int64
+}
+
+//NewBulkhead maxQueue <= 0 means rejecting at once when there is no free permit,
+//maxWait <= 0 means waiting until the context is done.
+func NewBulkhead(name string, maxConcurrent, maxQueue int, maxWait time.Duration) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	return &Bulkhead{
+		name:     name,
+		permits:  make(chan struct{}, maxConcurrent),
+		maxQueue: int64(maxQueue),
+		maxWait:  maxWait,
+	}
+}
+
+//GetName ...
+func (b *Bulkhead) GetName() string {
+	return b.name
+}
+
+//GetMaxConcurrent ...
+func (b *Bulkhead) GetMaxConcurrent() int {
+	return cap(b.permits)
+}
+
+//GetActiveCount returns the number of permits in use.
+func (b *Bulkhead) GetActiveCount() int {
+	return len(b.permits)
+}
+
+//GetWaitingCount returns the number of requests waiting for a permit.
+func (b *Bulkhead) GetWaitingCount() int64 {
+	return atomic.LoadInt64(&b.waiting)
+}
+
+//Acquire gets a permit, it fails with errors.BulkheadFull when the queue is full, the max wait expires
+//or the context is done. The permit must be given back with Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.permits <- struct{}{}:
+		metric.Bulkhead(b.name, 0, false)
+		return nil
+	default:
+	}
+
+	start := time.Now()
+	waiting := atomic.AddInt64(&b.waiting, 1)
+	defer atomic.AddInt64(&b.waiting, -1)
+	if waiting > b.maxQueue {
+		metric.Bulkhead(b.name, 0, true)
+		return errors.NewClientError(errors.BulkheadFull,
+			fmt.Errorf("bulkhead %s is full, max concurrent=%d, max queue=%d", b.name, cap(b.permits), b.maxQueue))
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var deadline <-chan time.Time
+	if b.maxWait > 0 {
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	select {
+	case b.permits <- struct{}{}:
+		metric.Bulkhead(b.name, time.Since(start), false)
+		return nil
+	case <-deadline:
+		metric.Bulkhead(b.name, time.Since(start), true)
+		return errors.NewClientError(errors.BulkheadFull,
+			fmt.Errorf("bulkhead %s has no free permit after waiting %s", b.name, b.maxWait))
+	case <-ctx.Done():
+		metric.Bulkhead(b.name, time.Since(start), true)
+		return errors.NewClientError(errors.BulkheadFull,
+			fmt.Errorf("bulkhead %s has no free permit, err=%v", b.name, ctx.Err()))
+	}
+}
+
+//Release gives back the permit got by Acquire.
+func (b *Bulkhead) Release() {
+	select {
+	case <-b.permits:
+	default:
+	}
+}
+
+//Execute runs the function with a permit.
+func (b *Bulkhead) Execute(ctx context.Context, run func() error) error {
+	if err := b.Acquire(ctx); err != nil {
+		return err
+	}
+	defer b.Release()
+	return run()
+}
@@ -0,0 +1,104 @@
+package bulkhead
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
+)
+
+//Registry holds the bulkhead of a client and the bulkheads of its api groups. A request of an api group needs
+//the permits of both, so a slow api group only uses up its own permits.
+type Registry struct {
+	client *Bulkhead
+
+	lock   *sync.RWMutex
+	groups map[string]*Bulkhead
+}
+
+//NewRegistry ...
+func NewRegistry(client *Bulkhead) *Registry {
+	return &Registry{
+		client: client,
+		lock:   &sync.RWMutex{},
+		groups: make(map[string]*Bulkhead),
+	}
+}
+
+//NewRegistryFromConfig nil if BulkheadSwitch is off. The api groups share BulkheadMaxQueue and BulkheadMaxWait
+//with the client.
+func NewRegistryFromConfig(clientConfig config.ClientConfig) *Registry {
+	if clientConfig == nil || !clientConfig.GetPropertyAsBool(config.BulkheadSwitch, config.DefaultBulkheadSwitch) {
+		return nil
+	}
+	name := clientConfig.GetClientName()
+	maxQueue := clientConfig.GetPropertyAsInteger(config.BulkheadMaxQueue, config.DefaultBulkheadMaxQueue)
+	maxWait := clientConfig.GetPropertyAsDuration(config.BulkheadMaxWait, config.DefaultBulkheadMaxWait)
+	registry := NewRegistry(NewBulkhead(name,
+		clientConfig.GetPropertyAsInteger(config.BulkheadMaxConcurrent, config.DefaultBulkheadMaxConcurrent), maxQueue, maxWait))
+
+	groups, err := ParseGroups(clientConfig.GetPropertyAsString(config.BulkheadGroups, config.DefaultBulkheadGroups))
+	if err != nil {
+		logger.Warnf(nil, "err_msg=invalid bulkhead groups||client=%s||err=%v", name, err)
+	}
+	for group, maxConcurrent := range groups {
+		registry.SetGroupBulkhead(group, NewBulkhead(name+":"+group, maxConcurrent, maxQueue, maxWait))
+	}
+	return registry
+}
+
+//ParseGroups parses the max concurrent requests of the api groups, e.g. "search:20,order:10".
+func ParseGroups(groupsStr string) (map[string]int, error) {
+	groups := make(map[string]int)
+	for _, item := range strings.Split(groupsStr, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		pos := strings.Index(item, ":")
+		if pos <= 0 || pos == len(item)-1 {
+			return groups, fmt.Errorf("invalid bulkhead group %q", item)
+		}
+		maxConcurrent, err := strconv.Atoi(strings.TrimSpace(item[pos+1:]))
+		if err != nil || maxConcurrent <= 0 {
+			return groups, fmt.Errorf("invalid max concurrent of bulkhead group %q", item)
+		}
+		groups[strings.TrimSpace(item[:pos])] = maxConcurrent
+	}
+	return groups, nil
+}
+
+//GetClientBulkhead ...
+func (r *Registry) GetClientBulkhead() *Bulkhead {
+	return r.client
+}
+
+//GetGroupBulkhead nil if the api group has no bulkhead.
+func (r *Registry) GetGroupBulkhead(group string) *Bulkhead {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.groups[group]
+}
+
+//SetGroupBulkhead ...
+func (r *Registry) SetGroupBulkhead(group string, bulkhead *Bulkhead) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.groups[group] = bulkhead
+}
+
+//Execute runs the function with the permits of the api group and the client, the group may be empty.
+func (r *Registry) Execute(ctx context.Context, group string, run func() error) error {
+	bulkhead := r.GetGroupBulkhead(group)
+	if bulkhead == nil {
+		return r.client.Execute(ctx, run)
+	}
+	//the permit of the group first, so that the waiters of a slow group do not hold the permits of the client
+	return bulkhead.Execute(ctx, func() error {
+		return r.client.Execute(ctx, run)
+	})
+}
@@ -0,0 +1,94 @@
+package bulkhead
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func isBulkheadFull(err error) bool {
+	clientErr, ok := err.(errors.ClientError)
+	return ok && clientErr.GetErrType() == errors.BulkheadFull && clientErr.GetErrType().IsClientThrottled()
+}
+
+//TestBulkhead ...
+func TestBulkhead(t *testing.T) {
+	bulkhead := NewBulkhead("test", 2, 1, 50*time.Millisecond)
+	ctx := context.Background()
+	assert.Nil(t, bulkhead.Acquire(ctx))
+	assert.Nil(t, bulkhead.Acquire(ctx))
+	assert.Equal(t, 2, bulkhead.GetActiveCount())
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- bulkhead.Acquire(ctx)
+	}()
+	for bulkhead.GetWaitingCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	//the queue is full
+	assert.True(t, isBulkheadFull(bulkhead.Acquire(ctx)))
+	//the waiter gets the permit when it is released
+	bulkhead.Release()
+	assert.Nil(t, <-acquired)
+
+	//the max wait expires
+	start := time.Now()
+	assert.True(t, isBulkheadFull(bulkhead.Acquire(ctx)))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	//the context is done
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	start = time.Now()
+	assert.True(t, isBulkheadFull(bulkhead.Acquire(cancelCtx)))
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+
+	bulkhead.Release()
+	bulkhead.Release()
+	assert.Equal(t, 0, bulkhead.GetActiveCount())
+	assert.Equal(t, int64(0), bulkhead.GetWaitingCount())
+}
+
+//TestRegistry ...
+func TestRegistry(t *testing.T) {
+	groups, err := ParseGroups("search:1, order:2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"search": 1, "order": 2}, groups)
+	_, err = ParseGroups("search")
+	assert.NotNil(t, err)
+	_, err = ParseGroups("search:0")
+	assert.NotNil(t, err)
+
+	registry := NewRegistry(NewBulkhead("test", 2, 0, 0))
+	registry.SetGroupBulkhead("search", NewBulkhead("test:search", 1, 0, 0))
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- registry.Execute(ctx, "search", func() error {
+			<-release
+			return nil
+		})
+	}()
+	for registry.GetGroupBulkhead("search").GetActiveCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	//the slow group uses up its own permits only
+	assert.True(t, isBulkheadFull(registry.Execute(ctx, "search", func() error {
+		return nil
+	})))
+	assert.Nil(t, registry.Execute(ctx, "order", func() error {
+		return nil
+	}))
+	assert.Equal(t, 1, registry.GetClientBulkhead().GetActiveCount())
+
+	close(release)
+	assert.Nil(t, <-done)
+	assert.Equal(t, 0, registry.GetClientBulkhead().GetActiveCount())
+}
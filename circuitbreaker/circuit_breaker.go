@@ -0,0 +1,242 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/stats"
+)
+
+//State the state of a circuit breaker.
+type State int32
+
+const (
+	//StateClosed the requests pass through, the results are counted in the rolling window.
+	StateClosed State = iota
+	//StateOpen the requests fail fast with errors.CircuitOpen.
+	StateOpen
+	//StateHalfOpen a limited number of probe requests pass through to decide whether to close again.
+	StateHalfOpen
+)
+
+var stateNames = map[State]string{
+	StateClosed:   "Closed",
+	StateOpen:     "Open",
+	StateHalfOpen: "HalfOpen",
+}
+
+//String ...
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+//Settings ...
+type Settings struct {
+	WindowSize       int           //the rolling window(unit: seconds)
+	MinRequests      int64         //the min number of requests in the window to trip
+	ErrorPercent     int           //the error rate to trip, 0 means disabled
+	SlowCallPercent  int           //the slow call rate to trip, 0 means disabled
+	SlowCallDuration time.Duration //the calls slower than this are slow calls
+	OpenDuration     time.Duration //how long to stay open before probing
+	HalfOpenProbes   int           //the number of probe requests in the half-open state
+}
+
+//NewSettingsFromConfig ...
+func NewSettingsFromConfig(clientConfig config.ClientConfig) Settings {
+	return Settings{
+		WindowSize:       clientConfig.GetPropertyAsInteger(config.CircuitBreakerWindowSize, config.DefaultCircuitBreakerWindowSize),
+		MinRequests:      int64(clientConfig.GetPropertyAsInteger(config.CircuitBreakerMinRequests, config.DefaultCircuitBreakerMinRequests)),
+		ErrorPercent:     clientConfig.GetPropertyAsInteger(config.CircuitBreakerErrorPercent, config.DefaultCircuitBreakerErrorPercent),
+		SlowCallPercent:  clientConfig.GetPropertyAsInteger(config.CircuitBreakerSlowCallPercent, config.DefaultCircuitBreakerSlowCallPercent),
+		SlowCallDuration: clientConfig.GetPropertyAsDuration(config.CircuitBreakerSlowCallDuration, config.DefaultCircuitBreakerSlowCallDuration),
+		OpenDuration:     clientConfig.GetPropertyAsDuration(config.CircuitBreakerOpenDuration, config.DefaultCircuitBreakerOpenDuration),
+		HalfOpenProbes:   clientConfig.GetPropertyAsInteger(config.CircuitBreakerHalfOpenProbes, config.DefaultCircuitBreakerHalfOpenProbes),
+	}
+}
+
+//CircuitBreaker trips on the error rate or the slow call rate of all the requests in a rolling window,
+//unlike the per server breaker which only counts the successive connection failures.
+type CircuitBreaker struct {
+	name     string
+	settings Settings
+
+	lock           *sync.Mutex
+	state          State
+	openedAt       time.Time
+	probes         int
+	probeSuccesses int
+	requests       *stats.RollingCounter
+	failures       *stats.RollingCounter
+	slowCalls      *stats.RollingCounter
+}
+
+//NewCircuitBreaker ...
+func NewCircuitBreaker(name string, settings Settings) *CircuitBreaker {
+	if settings.WindowSize <= 0 {
+		settings.WindowSize = config.DefaultCircuitBreakerWindowSize
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{
+		name:      name,
+		settings:  settings,
+		lock:      &sync.Mutex{},
+		state:     StateClosed,
+		requests:  stats.NewRollingCounter(settings.WindowSize),
+		failures:  stats.NewRollingCounter(settings.WindowSize),
+		slowCalls: stats.NewRollingCounter(settings.WindowSize),
+	}
+}
+
+//GetName ...
+func (b *CircuitBreaker) GetName() string {
+	return b.name
+}
+
+//GetState ...
+func (b *CircuitBreaker) GetState() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.checkOpenDuration(time.Now())
+	return b.state
+}
+
+//Allow returns errors.CircuitOpen if the request should fail fast, otherwise the caller must call Record
+//with the result of the request.
+func (b *CircuitBreaker) Allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.checkOpenDuration(time.Now())
+	switch b.state {
+	case StateOpen:
+		return errors.NewClientError(errors.CircuitOpen, fmt.Errorf("circuit breaker %s is open", b.name))
+	case StateHalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return errors.NewClientError(errors.CircuitOpen,
+				fmt.Errorf("circuit breaker %s is half-open, probes=%d", b.name, b.probes))
+		}
+		b.probes++
+	}
+	return nil
+}
+
+//Record counts the result of a request allowed by Allow. The requests rejected locally, e.g. throttled,
+//are not counted.
+func (b *CircuitBreaker) Record(err error, latency time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	ignored := isIgnored(err)
+	isFailure := !ignored && err != nil
+	isSlow := !ignored && b.settings.SlowCallDuration > 0 && latency >= b.settings.SlowCallDuration
+
+	switch b.state {
+	case StateClosed:
+		if ignored {
+			return
+		}
+		b.requests.Inc(1)
+		if isFailure {
+			b.failures.Inc(1)
+		}
+		if isSlow {
+			b.slowCalls.Inc(1)
+		}
+		if reason := b.getTripReason(); reason != "" {
+			b.setState(StateOpen, reason)
+		}
+	case StateHalfOpen:
+		if ignored {
+			//give the slot to another probe
+			if b.probes > 0 {
+				b.probes--
+			}
+			return
+		}
+		if isFailure || isSlow {
+			b.setState(StateOpen, "probe failed")
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.settings.HalfOpenProbes {
+			b.setState(StateClosed, "probes succeeded")
+		}
+	}
+}
+
+//Execute runs the function if the circuit breaker allows, and records its result.
+func (b *CircuitBreaker) Execute(run func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := run()
+	b.Record(err, time.Since(start))
+	return err
+}
+
+//Reset closes the circuit breaker and clears the rolling window.
+func (b *CircuitBreaker) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.setState(StateClosed, "reset")
+}
+
+func (b *CircuitBreaker) checkOpenDuration(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.settings.OpenDuration {
+		b.setState(StateHalfOpen, "open duration elapsed")
+	}
+}
+
+func (b *CircuitBreaker) getTripReason() string {
+	total := b.requests.Count()
+	if total <= 0 || total < b.settings.MinRequests {
+		return ""
+	}
+	if b.settings.ErrorPercent > 0 {
+		if errorPercent := b.failures.Count() * 100 / total; errorPercent >= int64(b.settings.ErrorPercent) {
+			return fmt.Sprintf("error rate %d%% of %d requests", errorPercent, total)
+		}
+	}
+	if b.settings.SlowCallPercent > 0 {
+		if slowCallPercent := b.slowCalls.Count() * 100 / total; slowCallPercent >= int64(b.settings.SlowCallPercent) {
+			return fmt.Sprintf("slow call rate %d%% of %d requests", slowCallPercent, total)
+		}
+	}
+	return ""
+}
+
+func (b *CircuitBreaker) setState(state State, reason string) {
+	from := b.state
+	b.state = state
+	b.probes = 0
+	b.probeSuccesses = 0
+	switch state {
+	case StateOpen:
+		b.openedAt = time.Now()
+	case StateClosed:
+		b.requests.Clear()
+		b.failures.Clear()
+		b.slowCalls.Clear()
+	}
+	if from != state {
+		logger.Warnf(nil, "err_msg=circuit breaker state changed||name=%s||from=%s||to=%s||reason=%s",
+			b.name, from, state, reason)
+	}
+}
+
+//isIgnored the requests rejected locally say nothing about the health of the upstream.
+func isIgnored(err error) bool {
+	clientErr, ok := err.(errors.ClientError)
+	if !ok {
+		return false
+	}
+	return clientErr.GetErrType().IsClientThrottled() || clientErr.GetErrType() == errors.CircuitOpen
+}
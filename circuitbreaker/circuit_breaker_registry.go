@@ -0,0 +1,97 @@
+package circuitbreaker
+
+import (
+	"sync"
+
+	"github.com/nienie/marathon/config"
+)
+
+//Registry holds the client level circuit breaker of a client, and the circuit breakers of its endpoints
+//if enabled, so that one broken endpoint does not fail the whole client.
+//The endpoints are named in the request config, the number of endpoint circuit breakers is bounded.
+type Registry struct {
+	name         string
+	settings     Settings
+	perEndpoint  bool
+	maxEndpoints int
+	client       *CircuitBreaker
+
+	lock      *sync.RWMutex
+	endpoints map[string]*CircuitBreaker
+}
+
+//NewRegistry ...
+func NewRegistry(name string, settings Settings, perEndpoint bool) *Registry {
+	return &Registry{
+		name:         name,
+		settings:     settings,
+		perEndpoint:  perEndpoint,
+		maxEndpoints: config.DefaultCircuitBreakerMaxEndpoints,
+		client:       NewCircuitBreaker(name, settings),
+		lock:         &sync.RWMutex{},
+		endpoints:    make(map[string]*CircuitBreaker),
+	}
+}
+
+//NewRegistryFromConfig nil if CircuitBreakerSwitch is off.
+func NewRegistryFromConfig(clientConfig config.ClientConfig) *Registry {
+	if clientConfig == nil ||
+		!clientConfig.GetPropertyAsBool(config.CircuitBreakerSwitch, config.DefaultCircuitBreakerSwitch) {
+		return nil
+	}
+	registry := NewRegistry(clientConfig.GetClientName(), NewSettingsFromConfig(clientConfig),
+		clientConfig.GetPropertyAsBool(config.CircuitBreakerPerEndpoint, config.DefaultCircuitBreakerPerEndpoint))
+	registry.SetMaxEndpoints(clientConfig.GetPropertyAsInteger(config.CircuitBreakerMaxEndpoints,
+		config.DefaultCircuitBreakerMaxEndpoints))
+	return registry
+}
+
+//SetMaxEndpoints ...
+func (r *Registry) SetMaxEndpoints(maxEndpoints int) {
+	r.lock.Lock()
+	r.maxEndpoints = maxEndpoints
+	r.lock.Unlock()
+}
+
+//GetClientCircuitBreaker ...
+func (r *Registry) GetClientCircuitBreaker() *CircuitBreaker {
+	return r.client
+}
+
+//GetEndpointCircuitBreaker nil if the endpoints have no circuit breakers, the endpoint is empty,
+//or there are already MaxEndpoints endpoint circuit breakers.
+func (r *Registry) GetEndpointCircuitBreaker(endpoint string) *CircuitBreaker {
+	if !r.perEndpoint || len(endpoint) == 0 {
+		return nil
+	}
+	r.lock.RLock()
+	breaker, ok := r.endpoints[endpoint]
+	r.lock.RUnlock()
+	if ok {
+		return breaker
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	breaker, ok = r.endpoints[endpoint]
+	if !ok {
+		if len(r.endpoints) >= r.maxEndpoints {
+			return nil
+		}
+		breaker = NewCircuitBreaker(r.name+":"+endpoint, r.settings)
+		r.endpoints[endpoint] = breaker
+	}
+	return breaker
+}
+
+//Execute runs the function through the circuit breakers of the endpoint and the client.
+func (r *Registry) Execute(endpoint string, run func() error) error {
+	breaker := r.GetEndpointCircuitBreaker(endpoint)
+	if breaker == nil {
+		return r.client.Execute(run)
+	}
+	//a rejection of the client breaker is ignored by the endpoint breaker
+	return breaker.Execute(func() error {
+		return r.client.Execute(run)
+	})
+}
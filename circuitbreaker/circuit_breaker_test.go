@@ -0,0 +1,153 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSettings() Settings {
+	return Settings{
+		WindowSize:       10,
+		MinRequests:      10,
+		ErrorPercent:     40,
+		SlowCallPercent:  50,
+		SlowCallDuration: 100 * time.Millisecond,
+		OpenDuration:     50 * time.Millisecond,
+		HalfOpenProbes:   2,
+	}
+}
+
+func isCircuitOpen(err error) bool {
+	clientErr, ok := err.(errors.ClientError)
+	return ok && clientErr.GetErrType() == errors.CircuitOpen
+}
+
+//TestCircuitBreaker ...
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker("test", newTestSettings())
+	failure := fmt.Errorf("upstream error")
+
+	//not enough requests to trip
+	for i := 0; i < 5; i++ {
+		breaker.Record(failure, time.Millisecond)
+	}
+	assert.Equal(t, StateClosed, breaker.GetState())
+
+	//the throttled requests are not counted
+	breaker.Record(errors.NewClientError(errors.ClientThrottled, nil), time.Millisecond)
+	assert.Equal(t, StateClosed, breaker.GetState())
+
+	//5 errors of 10 requests
+	for i := 0; i < 4; i++ {
+		breaker.Record(nil, time.Millisecond)
+	}
+	assert.Equal(t, StateClosed, breaker.GetState())
+	breaker.Record(nil, time.Millisecond)
+	assert.Equal(t, StateOpen, breaker.GetState())
+	assert.True(t, isCircuitOpen(breaker.Allow()))
+
+	//half-open allows the probes only
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, StateHalfOpen, breaker.GetState())
+	assert.Nil(t, breaker.Allow())
+	assert.Nil(t, breaker.Allow())
+	assert.True(t, isCircuitOpen(breaker.Allow()))
+
+	//a failed probe opens again
+	breaker.Record(failure, time.Millisecond)
+	assert.Equal(t, StateOpen, breaker.GetState())
+
+	//the succeeded probes close it
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, breaker.Execute(func() error {
+			return nil
+		}))
+	}
+	assert.Equal(t, StateClosed, breaker.GetState())
+
+	//slow calls trip it as well
+	for i := 0; i < 10; i++ {
+		breaker.Record(nil, 200*time.Millisecond)
+	}
+	assert.Equal(t, StateOpen, breaker.GetState())
+
+	breaker.Reset()
+	assert.Equal(t, StateClosed, breaker.GetState())
+	assert.Nil(t, breaker.Allow())
+}
+
+//TestRegistry ...
+func TestRegistry(t *testing.T) {
+	settings := newTestSettings()
+	settings.ErrorPercent = 60
+	registry := NewRegistry("test", settings, true)
+	failure := fmt.Errorf("upstream error")
+
+	for i := 0; i < 10; i++ {
+		registry.Execute("/ok", func() error {
+			return nil
+		})
+	}
+	for i := 0; i < 10; i++ {
+		registry.Execute("/broken", func() error {
+			return failure
+		})
+	}
+	//the broken endpoint does not fail the whole client
+	assert.Equal(t, StateOpen, registry.GetEndpointCircuitBreaker("/broken").GetState())
+	assert.Equal(t, StateClosed, registry.GetEndpointCircuitBreaker("/ok").GetState())
+	assert.Equal(t, StateClosed, registry.GetClientCircuitBreaker().GetState())
+	assert.Nil(t, registry.Execute("/ok", func() error {
+		return nil
+	}))
+
+	for i := 0; i < 20; i++ {
+		registry.GetClientCircuitBreaker().Record(failure, time.Millisecond)
+	}
+	assert.Equal(t, StateOpen, registry.GetClientCircuitBreaker().GetState())
+
+	executed := false
+	err := registry.Execute("/ok", func() error {
+		executed = true
+		return nil
+	})
+	assert.True(t, isCircuitOpen(err))
+	assert.False(t, executed)
+
+	//the rejection of the client breaker does not take the probe of the endpoint breaker
+	time.Sleep(60 * time.Millisecond)
+	registry.GetClientCircuitBreaker().Allow()
+	registry.GetClientCircuitBreaker().Allow()
+	registry.Execute("/broken", func() error {
+		return nil
+	})
+	endpointBreaker := registry.GetEndpointCircuitBreaker("/broken")
+	assert.Nil(t, endpointBreaker.Allow())
+	assert.Nil(t, endpointBreaker.Allow())
+
+	assert.Nil(t, NewRegistry("test", newTestSettings(), false).GetEndpointCircuitBreaker("/ok"))
+}
+
+//TestRegistryMaxEndpoints ...
+func TestRegistryMaxEndpoints(t *testing.T) {
+	registry := NewRegistry("test", newTestSettings(), true)
+	registry.SetMaxEndpoints(2)
+	assert.Nil(t, registry.GetEndpointCircuitBreaker(""))
+	assert.NotNil(t, registry.GetEndpointCircuitBreaker("getUser"))
+	assert.NotNil(t, registry.GetEndpointCircuitBreaker("listOrders"))
+	assert.Nil(t, registry.GetEndpointCircuitBreaker("getOrder"))
+	assert.Equal(t, "test:getUser", registry.GetEndpointCircuitBreaker("getUser").GetName())
+
+	//the endpoints beyond the bound still go through the client breaker
+	executed := false
+	assert.Nil(t, registry.Execute("getOrder", func() error {
+		executed = true
+		return nil
+	}))
+	assert.True(t, executed)
+}
@@ -14,13 +14,18 @@ var (
 	cf *clientFactory
 	ruleMap map[string]RuleConstructor
 	pingStrategyMap map[string]PingStrategyConstructor
+	pingMap map[string]PingConstructor
+	pingLock sync.RWMutex
 )
 
 //RuleConstructor ...
 type RuleConstructor func() loadbalancer.Rule
 
 //PingStrategyConstructor ...
-type PingStrategyConstructor func() ping.Strategy
+type PingStrategyConstructor func(config.ClientConfig) ping.Strategy
+
+//PingConstructor ...
+type PingConstructor func(config.ClientConfig) ping.Ping
 
 func init() {
 	ruleMap = map[string]RuleConstructor{
@@ -50,12 +55,26 @@ func init() {
 		},
 	}
 	pingStrategyMap = map[string]PingStrategyConstructor {
-		config.ParallelPingStrategy:		func()ping.Strategy {
+		config.ParallelPingStrategy:		func(config.ClientConfig) ping.Strategy {
 			return ping.NewParallelStrategy()
 		},
-		config.SerialPingStrategy:		func()ping.Strategy {
+		config.SerialPingStrategy:		func(config.ClientConfig) ping.Strategy {
 			return ping.NewSerialStrategy()
 		},
+		config.BoundedParallelPingStrategy:		func(clientConfig config.ClientConfig) ping.Strategy {
+			return ping.NewBoundedParallelStrategyFromConfig(clientConfig)
+		},
+	}
+	pingMap = map[string]PingConstructor{
+		config.HTTPPingType:		func(clientConfig config.ClientConfig) ping.Ping {
+			return ping.NewURLPingFromConfig(clientConfig)
+		},
+		config.TCPPingType:		func(clientConfig config.ClientConfig) ping.Ping {
+			return ping.NewTCPPingFromConfig(clientConfig)
+		},
+		config.NonePingType:		func(clientConfig config.ClientConfig) ping.Ping {
+			return nil
+		},
 	}
 	cf = newClientFactory()
 }
@@ -116,6 +135,23 @@ func GetHTTPClientByName(name string) *httpclient.LoadBalancerHTTPClient {
 	return cf.clients[name]
 }
 
+//RegisterPing registers a custom ping, which can be selected by the PingType config.
+func RegisterPing(pingType string, constructor PingConstructor) {
+	if len(pingType) == 0 || constructor == nil {
+		return
+	}
+	pingLock.Lock()
+	pingMap[pingType] = constructor
+	pingLock.Unlock()
+}
+
+func getPingConstructor(pingType string) (PingConstructor, bool) {
+	pingLock.RLock()
+	defer pingLock.RUnlock()
+	constructor, ok := pingMap[pingType]
+	return constructor, ok
+}
+
 //SetLogger ...
 func SetLogger(l logger.Logger) {
 	logger.SetLogger(l)
@@ -139,9 +175,17 @@ func GetBaseLoadBalancer(clientConfig config.ClientConfig) loadbalancer.LoadBala
 	if _, ok := pingStrategyMap[pingStrategyName]; !ok {
 		pingStrategyName = config.ParallelPingStrategy
 	}
-	strategy := pingStrategyMap[pingStrategyName]()
+	strategy := pingStrategyMap[pingStrategyName](clientConfig)
+
+	pingType := clientConfig.GetPropertyAsString(config.PingType, config.DefaultPingType)
+	pingConstructor, ok := getPingConstructor(pingType)
+	if !ok {
+		logger.Warnf(nil, "err_msg=unknown ping type %s, health check is disabled||client=%s", pingType, clientName)
+		pingConstructor, _ = getPingConstructor(config.NonePingType)
+	}
+	pingAction := pingConstructor(clientConfig)
 
-	lb = loadbalancer.NewBaseLoadBalancer(clientConfig, rule, nil, strategy)
+	lb = loadbalancer.NewBaseLoadBalancer(clientConfig, rule, pingAction, strategy)
 	cf.lbLock.Lock()
 	cf.loadBalancers[clientName] = lb
 	cf.lbLock.Unlock()
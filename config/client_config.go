@@ -114,20 +114,97 @@ func (c *DefaultClientConfig) LoadDefaultValues() {
 	c.putDefaultDurationProperty(CircuitTripMaxTimeout, DefaultCircuitTripMaxTimeout)
 	c.putDefaultDurationProperty(PingInterval, DefaultPingInterval)
 	c.putDefaultStringProperty(PingStrategy, DefaultPingStrategy)
+	c.putDefaultIntegerProperty(PingRise, DefaultPingRise)
+	c.putDefaultIntegerProperty(PingFall, DefaultPingFall)
+	c.putDefaultDurationProperty(PingJitter, DefaultPingJitter)
+	c.putDefaultDurationProperty(PingUnhealthyInterval, DefaultPingUnhealthyInterval)
+	c.putDefaultIntegerProperty(PingHistorySize, DefaultPingHistorySize)
+	c.putDefaultIntegerProperty(PingWorkers, DefaultPingWorkers)
+	c.putDefaultBoolProperty(PingShared, DefaultPingShared)
+	c.putDefaultBoolProperty(WaitForReady, DefaultWaitForReady)
+	c.putDefaultIntegerProperty(WaitForReadyMinServers, DefaultWaitForReadyMinServers)
+	c.putDefaultDurationProperty(WaitForReadyTimeout, DefaultWaitForReadyTimeout)
+	c.putDefaultDurationProperty(ServerWaitTimeout, DefaultServerWaitTimeout)
+	c.putDefaultIntegerProperty(ServerWaitQueueLength, DefaultServerWaitQueueLength)
+	c.putDefaultBoolProperty(CircuitBreakerSwitch, DefaultCircuitBreakerSwitch)
+	c.putDefaultBoolProperty(CircuitBreakerPerEndpoint, DefaultCircuitBreakerPerEndpoint)
+	c.putDefaultStringProperty(CircuitBreakerEndpoint, DefaultCircuitBreakerEndpoint)
+	c.putDefaultIntegerProperty(CircuitBreakerMaxEndpoints, DefaultCircuitBreakerMaxEndpoints)
+	c.putDefaultIntegerProperty(CircuitBreakerWindowSize, DefaultCircuitBreakerWindowSize)
+	c.putDefaultIntegerProperty(CircuitBreakerMinRequests, DefaultCircuitBreakerMinRequests)
+	c.putDefaultIntegerProperty(CircuitBreakerErrorPercent, DefaultCircuitBreakerErrorPercent)
+	c.putDefaultIntegerProperty(CircuitBreakerSlowCallPercent, DefaultCircuitBreakerSlowCallPercent)
+	c.putDefaultDurationProperty(CircuitBreakerSlowCallDuration, DefaultCircuitBreakerSlowCallDuration)
+	c.putDefaultDurationProperty(CircuitBreakerOpenDuration, DefaultCircuitBreakerOpenDuration)
+	c.putDefaultIntegerProperty(CircuitBreakerHalfOpenProbes, DefaultCircuitBreakerHalfOpenProbes)
+	c.putDefaultBoolProperty(BulkheadSwitch, DefaultBulkheadSwitch)
+	c.putDefaultIntegerProperty(BulkheadMaxConcurrent, DefaultBulkheadMaxConcurrent)
+	c.putDefaultIntegerProperty(BulkheadMaxQueue, DefaultBulkheadMaxQueue)
+	c.putDefaultDurationProperty(BulkheadMaxWait, DefaultBulkheadMaxWait)
+	c.putDefaultStringProperty(BulkheadGroups, DefaultBulkheadGroups)
+	c.putDefaultStringProperty(BulkheadGroup, DefaultBulkheadGroup)
 	c.putDefaultStringProperty(LoadBalancerRule, DefaultLoadBalancerRule)
 	c.putDefaultStringProperty(LoadBalancerKey, DefaultLoadBalancerKey)
 	c.putDefaultDurationProperty(RequestTimeout, DefaultRequestTimeout)
 	c.putDefaultDurationProperty(ListOfServersPollingInterval, DefaultListOfServersPollingInterval)
+	c.putDefaultStringProperty(ServerListCompositeMode, DefaultServerListCompositeMode)
+	c.putDefaultStringProperty(ServerListSnapshotFile, DefaultServerListSnapshotFile)
+	c.putDefaultDurationProperty(ServerListSnapshotMaxAge, DefaultServerListSnapshotMaxAge)
+	c.putDefaultStringProperty(ServerListFilters, DefaultServerListFilters)
+	c.putDefaultIntegerProperty(SubsetSize, DefaultSubsetSize)
+	c.putDefaultStringProperty(SubsetClientID, DefaultSubsetClientID)
 	c.putDefaultBoolProperty(ConcurrencyRateLimitSwitch, DefaultConcurrencyRateLimitSwitch)
 	c.putDefaultBoolProperty(TokenBucketRateLimitSwitch, DefaultTokenBucketRateLimitSwitch)
 	c.putDefaultIntegerProperty(TokenBucketCapacity, DefaultTokenBucketCapacity)
 	c.putDefaultDurationProperty(TokenBucketFillInterval, DefaultTokenBucketFillInterval)
 	c.putDefaultIntegerProperty(TokenBucketFillCount, DefaultTokenBucketFillCount)
+	c.putDefaultBoolProperty(AdaptiveConcurrencyRateLimitSwitch, DefaultAdaptiveConcurrencyRateLimitSwitch)
+	c.putDefaultStringProperty(AdaptiveConcurrencyAlgorithm, DefaultAdaptiveConcurrencyAlgorithm)
+	c.putDefaultStringProperty(AdaptiveConcurrencyScope, DefaultAdaptiveConcurrencyScope)
+	c.putDefaultIntegerProperty(AdaptiveConcurrencyInitialLimit, DefaultAdaptiveConcurrencyInitialLimit)
+	c.putDefaultIntegerProperty(AdaptiveConcurrencyMinLimit, DefaultAdaptiveConcurrencyMinLimit)
+	c.putDefaultIntegerProperty(AdaptiveConcurrencyMaxLimit, DefaultAdaptiveConcurrencyMaxLimit)
+	c.putDefaultDurationProperty(AdaptiveConcurrencyTimeout, DefaultAdaptiveConcurrencyTimeout)
+	c.putDefaultBoolProperty(SREThrottlingRateLimitSwitch, DefaultSREThrottlingRateLimitSwitch)
+	c.putDefaultStringProperty(SREThrottlingK, DefaultSREThrottlingK)
+	c.putDefaultIntegerProperty(SREThrottlingWindowSize, DefaultSREThrottlingWindowSize)
 	c.putDefaultBoolProperty(LeakyBucketRateLimitSwitch, DefaultLeakyBucketRateLimitSwitch)
 	c.putDefaultIntegerProperty(LeakyBucketCapacity, DefaultLeakyBucketCapacity)
 	c.putDefaultDurationProperty(LeakyBucketInterval, DefaultLeakyBucketInterval)
+	c.putDefaultBoolProperty(DistributedRateLimitSwitch, DefaultDistributedRateLimitSwitch)
+	c.putDefaultStringProperty(DistributedRateLimitStore, DefaultDistributedRateLimitStore)
+	c.putDefaultStringProperty(DistributedRateLimitKey, DefaultDistributedRateLimitKey)
+	c.putDefaultIntegerProperty(DistributedRateLimitCapacity, DefaultDistributedRateLimitCapacity)
+	c.putDefaultDurationProperty(DistributedRateLimitInterval, DefaultDistributedRateLimitInterval)
+	c.putDefaultIntegerProperty(DistributedRateLimitCount, DefaultDistributedRateLimitCount)
+	c.putDefaultIntegerProperty(DistributedRateLimitBatchSize, DefaultDistributedRateLimitBatchSize)
+	c.putDefaultIntegerProperty(DistributedRateLimitInstances, DefaultDistributedRateLimitInstances)
+	c.putDefaultDurationProperty(DistributedRateLimitTimeout, DefaultDistributedRateLimitTimeout)
+	c.putDefaultBoolProperty(QuotaRateLimitSwitch, DefaultQuotaRateLimitSwitch)
+	c.putDefaultStringProperty(QuotaAlgorithm, DefaultQuotaAlgorithm)
+	c.putDefaultStringProperty(QuotaScope, DefaultQuotaScope)
+	c.putDefaultIntegerProperty(QuotaLimit, DefaultQuotaLimit)
+	c.putDefaultDurationProperty(QuotaWindow, DefaultQuotaWindow)
+	c.putDefaultStringProperty(QuotaCalendarPeriod, DefaultQuotaCalendarPeriod)
+	c.putDefaultStringProperty(QuotaTimeZone, DefaultQuotaTimeZone)
+	c.putDefaultBoolProperty(RateLimitWaitSwitch, DefaultRateLimitWaitSwitch)
+	c.putDefaultDurationProperty(RateLimitMaxWait, DefaultRateLimitMaxWait)
+	c.putDefaultIntegerProperty(RateLimitMaxWaiters, DefaultRateLimitMaxWaiters)
+	c.putDefaultStringProperty(RateLimitRules, DefaultRateLimitRules)
 	c.putDefaultIntegerProperty(RequestCountsSlidingWindowSize, DefaultRequestCountsSlidingWindowSize)
 	c.putDefaultIntegerProperty(ResponseTimeWindowSize, DefaultResponseTimeWindowSize)
+	c.putDefaultBoolProperty(TLSInsecureSkipVerify, DefaultTLSInsecureSkipVerify)
+	c.putDefaultStringProperty(TLSServerName, DefaultTLSServerName)
+	c.putDefaultStringProperty(TLSCAFile, DefaultTLSCAFile)
+	c.putDefaultStringProperty(PingType, DefaultPingType)
+	c.putDefaultStringProperty(PingPath, DefaultPingPath)
+	c.putDefaultIntegerProperty(PingExpectedStatus, DefaultPingExpectedStatus)
+	c.putDefaultStringProperty(PingExpectedContent, DefaultPingExpectedContent)
+	c.putDefaultDurationProperty(PingTimeout, DefaultPingTimeout)
+	c.putDefaultStringProperty(PingHeaders, DefaultPingHeaders)
+	c.putDefaultStringProperty(PingSend, DefaultPingSend)
+	c.putDefaultStringProperty(PingExpectPrefix, DefaultPingExpectPrefix)
+	c.putDefaultStringProperty(PingExpectRegex, DefaultPingExpectRegex)
 }
 
 //LoadProperties ...
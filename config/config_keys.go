@@ -42,12 +42,84 @@ const (
 	PingInterval = "PingInterval"
 	//PingStrategy string ...
 	PingStrategy = "PingStrategy"
+	//PingRise int, consecutive successful pings to mark a server up ...
+	PingRise = "PingRise"
+	//PingFall int, consecutive failed pings to mark a server down ...
+	PingFall = "PingFall"
+	//PingJitter time.Duration, a random delay added to the ping schedule of every server ...
+	PingJitter = "PingJitter"
+	//PingUnhealthyInterval time.Duration, the ping interval while a server is down, 0 means PingInterval ...
+	PingUnhealthyInterval = "PingUnhealthyInterval"
+	//PingHistorySize int, the number of health check results kept for every server ...
+	PingHistorySize = "PingHistorySize"
+	//PingWorkers int, the number of workers of BoundedParallelPingStrategy ...
+	PingWorkers = "PingWorkers"
+	//PingShared bool, whether the health checks are shared with the other load balancers of the process ...
+	PingShared = "PingShared"
+	//WaitForReady bool, whether Do blocks until the load balancer is ready ...
+	WaitForReady = "WaitForReady"
+	//WaitForReadyMinServers int, the number of reachable servers for the load balancer to be ready ...
+	WaitForReadyMinServers = "WaitForReadyMinServers"
+	//WaitForReadyTimeout time.Duration, the max wait if the context has no deadline ...
+	WaitForReadyTimeout = "WaitForReadyTimeout"
+	//ServerWaitTimeout time.Duration, the max wait for an available server, 0 means failing at once ...
+	ServerWaitTimeout = "ServerWaitTimeout"
+	//ServerWaitQueueLength int, the max number of requests waiting for an available server ...
+	ServerWaitQueueLength = "ServerWaitQueueLength"
+	//CircuitBreakerSwitch bool, whether the client level circuit breaker is enabled ...
+	CircuitBreakerSwitch = "CircuitBreakerSwitch"
+	//CircuitBreakerPerEndpoint bool, whether every endpoint has its own circuit breaker as well ...
+	CircuitBreakerPerEndpoint = "CircuitBreakerPerEndpoint"
+	//CircuitBreakerEndpoint string, the endpoint(api name or route) of the request, set in the request config,
+	//the requests without it only go through the client level circuit breaker ...
+	CircuitBreakerEndpoint = "CircuitBreakerEndpoint"
+	//CircuitBreakerMaxEndpoints int, the max number of endpoint circuit breakers, the other endpoints only go through
+	//the client level circuit breaker ...
+	CircuitBreakerMaxEndpoints = "CircuitBreakerMaxEndpoints"
+	//CircuitBreakerWindowSize int, the rolling window of the circuit breaker(unit: seconds) ...
+	CircuitBreakerWindowSize = "CircuitBreakerWindowSize"
+	//CircuitBreakerMinRequests int, the min number of requests in the window to trip the circuit breaker ...
+	CircuitBreakerMinRequests = "CircuitBreakerMinRequests"
+	//CircuitBreakerErrorPercent int, the error rate to trip the circuit breaker, 0 means disabled ...
+	CircuitBreakerErrorPercent = "CircuitBreakerErrorPercent"
+	//CircuitBreakerSlowCallPercent int, the slow call rate to trip the circuit breaker, 0 means disabled ...
+	CircuitBreakerSlowCallPercent = "CircuitBreakerSlowCallPercent"
+	//CircuitBreakerSlowCallDuration time.Duration, the calls slower than this are slow calls ...
+	CircuitBreakerSlowCallDuration = "CircuitBreakerSlowCallDuration"
+	//CircuitBreakerOpenDuration time.Duration, how long the circuit breaker stays open before probing ...
+	CircuitBreakerOpenDuration = "CircuitBreakerOpenDuration"
+	//CircuitBreakerHalfOpenProbes int, the number of probe requests allowed in the half-open state ...
+	CircuitBreakerHalfOpenProbes = "CircuitBreakerHalfOpenProbes"
+	//BulkheadSwitch bool, whether the bulkhead of the client is enabled ...
+	BulkheadSwitch = "BulkheadSwitch"
+	//BulkheadMaxConcurrent int, the max number of concurrent requests of the client ...
+	BulkheadMaxConcurrent = "BulkheadMaxConcurrent"
+	//BulkheadMaxQueue int, the max number of requests waiting for a permit, 0 means rejecting at once ...
+	BulkheadMaxQueue = "BulkheadMaxQueue"
+	//BulkheadMaxWait time.Duration, the max wait for a permit, 0 means waiting until the context is done ...
+	BulkheadMaxWait = "BulkheadMaxWait"
+	//BulkheadGroups string, the max concurrent requests of the api groups, e.g. "search:20,order:10" ...
+	BulkheadGroups = "BulkheadGroups"
+	//BulkheadGroup string, the api group of the request, set in the request config ...
+	BulkheadGroup = "BulkheadGroup"
 	//LoadBalancerRule string ...
 	LoadBalancerRule = "LoadBalancerRule"
 	//LoadBalancerKey string ...
 	LoadBalancerKey = "LoadBalancerKey"
 	//ListOfServersPollingInterval time.Duration ...
 	ListOfServersPollingInterval = "ListOfServersPollingInterval"
+	//ServerListCompositeMode string failover|merge ...
+	ServerListCompositeMode = "ServerListCompositeMode"
+	//ServerListSnapshotFile string, the file to persist the last known server list, empty means disabled ...
+	ServerListSnapshotFile = "ServerListSnapshotFile"
+	//ServerListSnapshotMaxAge time.Duration ...
+	ServerListSnapshotMaxAge = "ServerListSnapshotMaxAge"
+	//ServerListFilters string, e.g. "cluster:bj,deny:10.0.0.5" ...
+	ServerListFilters = "ServerListFilters"
+	//SubsetSize int, the number of servers a client connects to, 0 means disabled ...
+	SubsetSize = "SubsetSize"
+	//SubsetClientID string, a stable id of the client instance, e.g. the ordinal, hostname:pid by default ...
+	SubsetClientID = "SubsetClientID"
 	//ConcurrencyRateLimitSwitch bool
 	ConcurrencyRateLimitSwitch = "ConcurrencyRateLimitSwitch"
 	//TokenBucketRateLimitSwitch boll ...
@@ -58,12 +130,115 @@ const (
 	TokenBucketFillInterval = "TokenBucketFillInterval"
 	//TokenBucketFillCount int ...
 	TokenBucketFillCount = "TokenBucketFillCount"
+	//AdaptiveConcurrencyRateLimitSwitch bool ...
+	AdaptiveConcurrencyRateLimitSwitch = "AdaptiveConcurrencyRateLimitSwitch"
+	//AdaptiveConcurrencyAlgorithm string AIMD|Vegas|Gradient2 ...
+	AdaptiveConcurrencyAlgorithm = "AdaptiveConcurrencyAlgorithm"
+	//AdaptiveConcurrencyScope string client|server, whether the limit is shared by the servers of the client ...
+	AdaptiveConcurrencyScope = "AdaptiveConcurrencyScope"
+	//AdaptiveConcurrencyInitialLimit int ...
+	AdaptiveConcurrencyInitialLimit = "AdaptiveConcurrencyInitialLimit"
+	//AdaptiveConcurrencyMinLimit int ...
+	AdaptiveConcurrencyMinLimit = "AdaptiveConcurrencyMinLimit"
+	//AdaptiveConcurrencyMaxLimit int ...
+	AdaptiveConcurrencyMaxLimit = "AdaptiveConcurrencyMaxLimit"
+	//AdaptiveConcurrencyTimeout time.Duration, the requests slower than this are treated as dropped by AIMD ...
+	AdaptiveConcurrencyTimeout = "AdaptiveConcurrencyTimeout"
+	//SREThrottlingRateLimitSwitch bool ...
+	SREThrottlingRateLimitSwitch = "SREThrottlingRateLimitSwitch"
+	//SREThrottlingK string, a float, the client starts dropping when the requests exceed K times the accepts ...
+	SREThrottlingK = "SREThrottlingK"
+	//SREThrottlingWindowSize int, the rolling window of the requests and the accepts(unit: seconds) ...
+	SREThrottlingWindowSize = "SREThrottlingWindowSize"
 	//LeakyBucketRateLimitSwitch bool ...
 	LeakyBucketRateLimitSwitch = "LeakyBucketRateLimitSwitch"
 	//LeakyBucketCapacity int ...
 	LeakyBucketCapacity = "LeakyBucketCapacity"
 	//LeakyBucketInterval ...
 	LeakyBucketInterval = "LeakyBucketInterval"
+	//DistributedRateLimitSwitch bool, whether the requests take the tokens from a shared store ...
+	DistributedRateLimitSwitch = "DistributedRateLimitSwitch"
+	//DistributedRateLimitStore string, the name of the store registered by ratelimit.RegisterStore ...
+	DistributedRateLimitStore = "DistributedRateLimitStore"
+	//DistributedRateLimitKey string, the key of the shared bucket, the client name if it is empty ...
+	DistributedRateLimitKey = "DistributedRateLimitKey"
+	//DistributedRateLimitCapacity int, the capacity of the shared bucket ...
+	DistributedRateLimitCapacity = "DistributedRateLimitCapacity"
+	//DistributedRateLimitInterval time.Duration, Count tokens are put into the shared bucket every Interval ...
+	DistributedRateLimitInterval = "DistributedRateLimitInterval"
+	//DistributedRateLimitCount int ...
+	DistributedRateLimitCount = "DistributedRateLimitCount"
+	//DistributedRateLimitBatchSize int, the max number of tokens taken from the store at once, the tokens not used
+	//within Interval are dropped ...
+	DistributedRateLimitBatchSize = "DistributedRateLimitBatchSize"
+	//DistributedRateLimitInstances int, the number of instances sharing the bucket, every instance limits the requests
+	//locally with its share of the rate when the store is unavailable ...
+	DistributedRateLimitInstances = "DistributedRateLimitInstances"
+	//DistributedRateLimitTimeout time.Duration, the deadline of a call to the store, the requests are limited locally
+	//when it is exceeded ...
+	DistributedRateLimitTimeout = "DistributedRateLimitTimeout"
+	//QuotaRateLimitSwitch bool, whether the requests are limited by a quota, e.g. 10,000 requests per hour ...
+	QuotaRateLimitSwitch = "QuotaRateLimitSwitch"
+	//QuotaAlgorithm string, SlidingWindowLog|SlidingWindowCounter|CalendarWindow ...
+	QuotaAlgorithm = "QuotaAlgorithm"
+	//QuotaScope string, client|server, whether the quota is of the client or of every server ...
+	QuotaScope = "QuotaScope"
+	//QuotaLimit int, the max number of requests in the window ...
+	QuotaLimit = "QuotaLimit"
+	//QuotaWindow time.Duration, the window of SlidingWindowLog and SlidingWindowCounter ...
+	QuotaWindow = "QuotaWindow"
+	//QuotaCalendarPeriod string, hour|day|week|month, the window of CalendarWindow ...
+	QuotaCalendarPeriod = "QuotaCalendarPeriod"
+	//QuotaTimeZone string, the time zone of CalendarWindow, e.g. Asia/Shanghai, Local by default ...
+	QuotaTimeZone = "QuotaTimeZone"
+	//RateLimitWaitSwitch bool, whether the request waits for the token bucket and the leaky bucket instead of being rejected ...
+	RateLimitWaitSwitch = "RateLimitWaitSwitch"
+	//RateLimitMaxWait time.Duration, the max wait for the rate limits, the deadline of the context also applies ...
+	RateLimitMaxWait = "RateLimitMaxWait"
+	//RateLimitMaxWaiters int, the max number of requests of the client waiting for the rate limits ...
+	RateLimitMaxWaiters = "RateLimitMaxWaiters"
+	//RateLimitRules string, the names of the rate limit rules, e.g. "tenant,users", every rule is configured by the
+	//properties with the prefix RateLimitRulePrefix + name, e.g. RateLimitRule.tenant.Key ...
+	RateLimitRules = "RateLimitRules"
+	//RateLimitRulePrefix the prefix of the properties of the rate limit rules ...
+	RateLimitRulePrefix = "RateLimitRule."
+	//RateLimitRuleKey string, the dimensions of the key joined with "+", e.g. "header:X-Tenant+route", see RateLimitKey ...
+	RateLimitRuleKey = "Key"
+	//RateLimitRuleRoutes string, the route templates of the route dimension, e.g. "/users/{id},/users/{id}/orders",
+	//the requests matching none of them are not limited by the rule, and the path is used if it is empty ...
+	RateLimitRuleRoutes = "Routes"
+	//RateLimitRuleAlgorithm string, TokenBucket|LeakyBucket ...
+	RateLimitRuleAlgorithm = "Algorithm"
+	//RateLimitRuleCapacity int, the capacity of the bucket ...
+	RateLimitRuleCapacity = "Capacity"
+	//RateLimitRuleInterval time.Duration, the fill interval of TokenBucket or the leak interval of LeakyBucket ...
+	RateLimitRuleInterval = "Interval"
+	//RateLimitRuleCount int, the fill count of TokenBucket ...
+	RateLimitRuleCount = "Count"
+	//TLSInsecureSkipVerify bool ...
+	TLSInsecureSkipVerify = "TLSInsecureSkipVerify"
+	//TLSServerName string ...
+	TLSServerName = "TLSServerName"
+	//TLSCAFile string, the PEM file of the root CAs ...
+	TLSCAFile = "TLSCAFile"
+	//PingType string http|tcp|none ...
+	PingType = "PingType"
+	//PingPath string ...
+	PingPath = "PingPath"
+	//PingExpectedStatus int ...
+	PingExpectedStatus = "PingExpectedStatus"
+	//PingExpectedContent string ...
+	PingExpectedContent = "PingExpectedContent"
+	//PingTimeout time.Duration ...
+	PingTimeout = "PingTimeout"
+	//PingHeaders string, e.g. "Host:example.com;X-Health-Check:marathon" ...
+	PingHeaders = "PingHeaders"
+	//PingSend string, the payload written by the tcp ping, e.g. "PING\r\n" ...
+	PingSend = "PingSend"
+	//PingExpectPrefix string, the expected prefix of the tcp ping reply, e.g. "+PONG" ...
+	PingExpectPrefix = "PingExpectPrefix"
+	//PingExpectRegex string, the regexp to match the tcp ping reply ...
+	PingExpectRegex = "PingExpectRegex"
 )
 
 //config default value
@@ -102,12 +277,82 @@ const (
 	DefaultPingInterval = 5 * time.Second
 	//DefaultPingStrategy ...
 	DefaultPingStrategy = "ParallelPingStrategy"
+	//DefaultPingRise ...
+	DefaultPingRise = 1
+	//DefaultPingFall ...
+	DefaultPingFall = 1
+	//DefaultPingJitter ...
+	DefaultPingJitter = time.Duration(0)
+	//DefaultPingUnhealthyInterval ...
+	DefaultPingUnhealthyInterval = time.Duration(0)
+	//DefaultPingHistorySize ...
+	DefaultPingHistorySize = 10
+	//DefaultPingWorkers ...
+	DefaultPingWorkers = 10
+	//DefaultPingShared ...
+	DefaultPingShared = false
+	//DefaultWaitForReady ...
+	DefaultWaitForReady = false
+	//DefaultWaitForReadyMinServers ...
+	DefaultWaitForReadyMinServers = 1
+	//DefaultWaitForReadyTimeout ...
+	DefaultWaitForReadyTimeout = 5 * time.Second
+	//DefaultServerWaitTimeout ...
+	DefaultServerWaitTimeout = 0
+	//DefaultServerWaitQueueLength ...
+	DefaultServerWaitQueueLength = 100
+	//DefaultCircuitBreakerSwitch ...
+	DefaultCircuitBreakerSwitch = false
+	//DefaultCircuitBreakerPerEndpoint ...
+	DefaultCircuitBreakerPerEndpoint = false
+	//DefaultCircuitBreakerEndpoint ...
+	DefaultCircuitBreakerEndpoint = ""
+	//DefaultCircuitBreakerMaxEndpoints ...
+	DefaultCircuitBreakerMaxEndpoints = 100
+	//DefaultCircuitBreakerWindowSize ...
+	DefaultCircuitBreakerWindowSize = 10
+	//DefaultCircuitBreakerMinRequests ...
+	DefaultCircuitBreakerMinRequests = 20
+	//DefaultCircuitBreakerErrorPercent ...
+	DefaultCircuitBreakerErrorPercent = 50
+	//DefaultCircuitBreakerSlowCallPercent ...
+	DefaultCircuitBreakerSlowCallPercent = 0
+	//DefaultCircuitBreakerSlowCallDuration ...
+	DefaultCircuitBreakerSlowCallDuration = 1 * time.Second
+	//DefaultCircuitBreakerOpenDuration ...
+	DefaultCircuitBreakerOpenDuration = 5 * time.Second
+	//DefaultCircuitBreakerHalfOpenProbes ...
+	DefaultCircuitBreakerHalfOpenProbes = 3
+	//DefaultBulkheadSwitch ...
+	DefaultBulkheadSwitch = false
+	//DefaultBulkheadMaxConcurrent ...
+	DefaultBulkheadMaxConcurrent = 100
+	//DefaultBulkheadMaxQueue ...
+	DefaultBulkheadMaxQueue = 50
+	//DefaultBulkheadMaxWait ...
+	DefaultBulkheadMaxWait = 100 * time.Millisecond
+	//DefaultBulkheadGroups ...
+	DefaultBulkheadGroups = ""
+	//DefaultBulkheadGroup ...
+	DefaultBulkheadGroup = ""
 	//DefaultLoadBalancerRule ...
 	DefaultLoadBalancerRule = "SmoothWeightedRoundRobinRule"
 	//DefaultLoadBalancerKey ...
 	DefaultLoadBalancerKey = "marathon"
 	//DefaultListOfServersPollingInterval ...
 	DefaultListOfServersPollingInterval = 30 * time.Second
+	//DefaultServerListCompositeMode ...
+	DefaultServerListCompositeMode = "failover"
+	//DefaultServerListSnapshotFile ...
+	DefaultServerListSnapshotFile = ""
+	//DefaultServerListSnapshotMaxAge ...
+	DefaultServerListSnapshotMaxAge = 24 * time.Hour
+	//DefaultServerListFilters ...
+	DefaultServerListFilters = ""
+	//DefaultSubsetSize ...
+	DefaultSubsetSize = 0
+	//DefaultSubsetClientID ...
+	DefaultSubsetClientID = ""
 	//DefaultConcurrencyRateLimitSwitch ...
 	DefaultConcurrencyRateLimitSwitch = false
 	//DefaultTokenBucketRateLimitSwitch ...
@@ -118,16 +363,114 @@ const (
 	DefaultTokenBucketFillInterval = 100 * time.Millisecond
 	//DefaultTokenBucketFillCount ...
 	DefaultTokenBucketFillCount = 2
+	//DefaultAdaptiveConcurrencyRateLimitSwitch ...
+	DefaultAdaptiveConcurrencyRateLimitSwitch = false
+	//DefaultAdaptiveConcurrencyAlgorithm ...
+	DefaultAdaptiveConcurrencyAlgorithm = AIMDLimitAlgorithm
+	//DefaultAdaptiveConcurrencyScope ...
+	DefaultAdaptiveConcurrencyScope = ClientLimitScope
+	//DefaultAdaptiveConcurrencyInitialLimit ...
+	DefaultAdaptiveConcurrencyInitialLimit = 20
+	//DefaultAdaptiveConcurrencyMinLimit ...
+	DefaultAdaptiveConcurrencyMinLimit = 1
+	//DefaultAdaptiveConcurrencyMaxLimit ...
+	DefaultAdaptiveConcurrencyMaxLimit = 200
+	//DefaultAdaptiveConcurrencyTimeout ...
+	DefaultAdaptiveConcurrencyTimeout = 1 * time.Second
+	//DefaultSREThrottlingRateLimitSwitch ...
+	DefaultSREThrottlingRateLimitSwitch = false
+	//DefaultSREThrottlingK ...
+	DefaultSREThrottlingK = "2"
+	//DefaultSREThrottlingWindowSize ...
+	DefaultSREThrottlingWindowSize = 120
 	//DefaultLeakyBucketRateLimitSwitch ...
 	DefaultLeakyBucketRateLimitSwitch = false
 	//DefaultLeakyBucketCapacity ...
 	DefaultLeakyBucketCapacity = 50
 	//DefaultLeakyBucketInterval ...
 	DefaultLeakyBucketInterval = 20 * time.Millisecond
+	//DefaultDistributedRateLimitSwitch ...
+	DefaultDistributedRateLimitSwitch = false
+	//DefaultDistributedRateLimitStore ...
+	DefaultDistributedRateLimitStore = "default"
+	//DefaultDistributedRateLimitKey ...
+	DefaultDistributedRateLimitKey = ""
+	//DefaultDistributedRateLimitCapacity ...
+	DefaultDistributedRateLimitCapacity = 100
+	//DefaultDistributedRateLimitInterval ...
+	DefaultDistributedRateLimitInterval = 1 * time.Second
+	//DefaultDistributedRateLimitCount ...
+	DefaultDistributedRateLimitCount = 100
+	//DefaultDistributedRateLimitBatchSize ...
+	DefaultDistributedRateLimitBatchSize = 10
+	//DefaultDistributedRateLimitInstances ...
+	DefaultDistributedRateLimitInstances = 1
+	//DefaultDistributedRateLimitTimeout ...
+	DefaultDistributedRateLimitTimeout = 50 * time.Millisecond
+	//DefaultQuotaRateLimitSwitch ...
+	DefaultQuotaRateLimitSwitch = false
+	//DefaultQuotaAlgorithm ...
+	DefaultQuotaAlgorithm = SlidingWindowCounterQuotaAlgorithm
+	//DefaultQuotaScope ...
+	DefaultQuotaScope = ClientLimitScope
+	//DefaultQuotaLimit ...
+	DefaultQuotaLimit = 10000
+	//DefaultQuotaWindow ...
+	DefaultQuotaWindow = 1 * time.Hour
+	//DefaultQuotaCalendarPeriod ...
+	DefaultQuotaCalendarPeriod = DayCalendarPeriod
+	//DefaultQuotaTimeZone ...
+	DefaultQuotaTimeZone = "Local"
+	//DefaultRateLimitWaitSwitch ...
+	DefaultRateLimitWaitSwitch = false
+	//DefaultRateLimitMaxWait ...
+	DefaultRateLimitMaxWait = 1 * time.Second
+	//DefaultRateLimitMaxWaiters ...
+	DefaultRateLimitMaxWaiters = 100
+	//DefaultRateLimitRules ...
+	DefaultRateLimitRules = ""
+	//DefaultRateLimitRuleKey ...
+	DefaultRateLimitRuleKey = ClientLimitScope
+	//DefaultRateLimitRuleAlgorithm ...
+	DefaultRateLimitRuleAlgorithm = TokenBucketRateLimitAlgorithm
 	//DefaultRequestCountsSlidingWindowSize ...
 	DefaultRequestCountsSlidingWindowSize = 300 // means save 300 seconds data
 	//DefaultResponseTimeWindowSize ...
 	DefaultResponseTimeWindowSize = 300 // means save 300 seconds data
+	//DefaultTLSInsecureSkipVerify ...
+	DefaultTLSInsecureSkipVerify = false
+	//DefaultTLSServerName ...
+	DefaultTLSServerName = ""
+	//DefaultTLSCAFile ...
+	DefaultTLSCAFile = ""
+	//DefaultPingType ...
+	DefaultPingType = NonePingType
+	//DefaultPingPath ...
+	DefaultPingPath = "/"
+	//DefaultPingExpectedStatus ...
+	DefaultPingExpectedStatus = 200
+	//DefaultPingExpectedContent ...
+	DefaultPingExpectedContent = ""
+	//DefaultPingTimeout ...
+	DefaultPingTimeout = 1 * time.Second
+	//DefaultPingHeaders ...
+	DefaultPingHeaders = ""
+	//DefaultPingSend ...
+	DefaultPingSend = ""
+	//DefaultPingExpectPrefix ...
+	DefaultPingExpectPrefix = ""
+	//DefaultPingExpectRegex ...
+	DefaultPingExpectRegex = ""
+)
+
+//PingType ...
+const (
+	//HTTPPingType ...
+	HTTPPingType = "http"
+	//TCPPingType ...
+	TCPPingType = "tcp"
+	//NonePingType ...
+	NonePingType = "none"
 )
 
 //PingStrategy ...
@@ -136,6 +479,68 @@ const (
 	SerialPingStrategy = "SerialPingStrategy"
 	//ParallelPingStrategy ...
 	ParallelPingStrategy = "ParallelPingStrategy"
+	//BoundedParallelPingStrategy ...
+	BoundedParallelPingStrategy = "BoundedParallelPingStrategy"
+)
+
+//AdaptiveConcurrencyAlgorithm ...
+const (
+	//AIMDLimitAlgorithm additive increase, multiplicative decrease on the drops ...
+	AIMDLimitAlgorithm = "AIMD"
+	//VegasLimitAlgorithm estimates the queue from the min latency ...
+	VegasLimitAlgorithm = "Vegas"
+	//Gradient2LimitAlgorithm follows the gradient of the long term and short term latency ...
+	Gradient2LimitAlgorithm = "Gradient2"
+)
+
+//AdaptiveConcurrencyScope ...
+const (
+	//ClientLimitScope ...
+	ClientLimitScope = "client"
+	//ServerLimitScope ...
+	ServerLimitScope = "server"
+)
+
+//QuotaAlgorithm ...
+const (
+	//SlidingWindowLogQuotaAlgorithm keeps the time of every request in the window, exact ...
+	SlidingWindowLogQuotaAlgorithm = "SlidingWindowLog"
+	//SlidingWindowCounterQuotaAlgorithm estimates the requests in the window from two fixed windows ...
+	SlidingWindowCounterQuotaAlgorithm = "SlidingWindowCounter"
+	//CalendarWindowQuotaAlgorithm a fixed window which resets at the start of every calendar period ...
+	CalendarWindowQuotaAlgorithm = "CalendarWindow"
+)
+
+//QuotaCalendarPeriod ...
+const (
+	//HourCalendarPeriod ...
+	HourCalendarPeriod = "hour"
+	//DayCalendarPeriod ...
+	DayCalendarPeriod = "day"
+	//WeekCalendarPeriod starts on Monday ...
+	WeekCalendarPeriod = "week"
+	//MonthCalendarPeriod ...
+	MonthCalendarPeriod = "month"
+)
+
+//RateLimitKey the dimensions of the key of a rate limit rule
+const (
+	//ClientLimitKey the requests of the client share the limit ...
+	ClientLimitKey = ClientLimitScope
+	//ServerLimitKey the requests to a server share the limit ...
+	ServerLimitKey = ServerLimitScope
+	//RouteLimitKey the requests matching a route template share the limit ...
+	RouteLimitKey = "route"
+	//HeaderLimitKeyPrefix the requests with the same value of the header share the limit, e.g. "header:X-Tenant" ...
+	HeaderLimitKeyPrefix = "header:"
+)
+
+//RateLimitRuleAlgorithm ...
+const (
+	//TokenBucketRateLimitAlgorithm ...
+	TokenBucketRateLimitAlgorithm = "TokenBucket"
+	//LeakyBucketRateLimitAlgorithm ...
+	LeakyBucketRateLimitAlgorithm = "LeakyBucket"
 )
 
 //LoadBalancer Rule
@@ -34,6 +34,14 @@ const (
 	CacheMissing
 	//AbortExecutionException ...
 	AbortExecutionException
+	//LoadBalancerNotReady the load balancer does not have enough reachable servers.
+	LoadBalancerNotReady
+	//NoAvailableServer the load balancer does not have an available server for the request.
+	NoAvailableServer
+	//CircuitOpen the circuit breaker of the client or the endpoint is open, the request fails fast.
+	CircuitOpen
+	//BulkheadFull the bulkhead of the client or the api group has no free permit, a subtype of ClientThrottled.
+	BulkheadFull
 )
 
 var errorTypeNameMap = map[ErrorType]string{
@@ -52,6 +60,21 @@ var errorTypeNameMap = map[ErrorType]string{
 	NoRouteToHostException:            "NoRouteToHostException",
 	CacheMissing:                      "CacheMissing",
 	AbortExecutionException:           "AbortExecutionException",
+	LoadBalancerNotReady:              "LoadBalancerNotReady",
+	NoAvailableServer:                 "NoAvailableServer",
+	CircuitOpen:                       "CircuitOpen",
+	BulkheadFull:                      "BulkheadFull",
+}
+
+//clientThrottledTypes the subtypes of ClientThrottled
+var clientThrottledTypes = map[ErrorType]bool{
+	ClientThrottled: true,
+	BulkheadFull:    true,
+}
+
+//IsClientThrottled whether the request is rejected locally by the client.
+func (e ErrorType) IsClientThrottled() bool {
+	return clientThrottledTypes[e]
 }
 
 //GetName ...
@@ -2,6 +2,7 @@ package httpclient
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 
@@ -9,7 +10,10 @@ import (
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/errors"
 	"github.com/nienie/marathon/loadbalancer"
+	"github.com/nienie/marathon/loadbalancer/command"
 	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/metric"
+	httputil "github.com/nienie/marathon/utils/http"
 
 	transport "github.com/mreiferson/go-httpclient"
 )
@@ -60,6 +64,8 @@ type LoadBalancerHTTPClient struct {
 	BeforeHooks    []BeforeHTTPHook
 	AfterHooks     []AfterHTTHook
 	ClientConfig   config.ClientConfig
+	Fallback       Fallback
+	tlsConfig      *tls.Config
 }
 
 //NewHTTPLoadBalancerClient ...
@@ -68,14 +74,18 @@ func NewHTTPLoadBalancerClient(clientConfig config.ClientConfig, lb loadbalancer
 	loadBalancerContext := loadbalancer.NewLoadBalancerContext(clientConfig, lb)
 	//create load balancer client
 	loadBalancerClient := &loadbalancer.BaseLoadBalancerClient{
-		loadBalancerContext,
-		nil,
+		Context: loadBalancerContext,
 	}
 	//create transport
+	tlsConfig, err := httputil.NewTLSConfig(clientConfig)
+	if err != nil {
+		logger.Warnf(nil, "err_msg=invalid tls settings||client=%s||err=%v", clientConfig.GetClientName(), err)
+	}
 	trans := &transport.Transport{
 		ConnectTimeout:   clientConfig.GetPropertyAsDuration(config.ConnectTimeout, config.DefaultConnectTimeout),
 		ReadWriteTimeout: clientConfig.GetPropertyAsDuration(config.ReadWriteTimeout, config.DefaultReadWriteTimeout),
 		RequestTimeout:   clientConfig.GetPropertyAsDuration(config.RequestTimeout, config.DefaultRequestTimeout),
+		TLSClientConfig:  tlsConfig,
 	}
 	//create original http.client
 	originalClient := &http.Client{
@@ -89,6 +99,7 @@ func NewHTTPLoadBalancerClient(clientConfig config.ClientConfig, lb loadbalancer
 		BeforeHooks:            make([]BeforeHTTPHook, 0),
 		AfterHooks:             []AfterHTTHook{loggerAfterHook},
 		ClientConfig:			clientConfig,
+		tlsConfig:              tlsConfig,
 	}
 	//load balancer context correlate with http client
 	loadBalancerClient.Client = httpClient
@@ -101,16 +112,74 @@ func (c *LoadBalancerHTTPClient) Do(ctx context.Context, request *HTTPRequest, r
 		return nil, fmt.Errorf("wrong type, request is nil")
 	}
 	c.beforeHTTPHook(ctx, request)
-	resp, err := c.BaseLoadBalancerClient.ExecuteWithLoadBalancer(ctx, request, requestConfig)
+	history := command.NewExecutionHistory()
+	if err := c.waitForReady(ctx, requestConfig); err != nil {
+		return c.fallback(ctx, request, err, history)
+	}
+	resp, err := c.BaseLoadBalancerClient.ExecuteWithHistory(ctx, request, requestConfig, history)
 	if err != nil || resp == nil {
-		c.afterHTTPHook(ctx, request, nil, err)
-		return nil, err
+		return c.fallback(ctx, request, err, history)
 	}
 	response := resp.(*HTTPResponse)
 	c.afterHTTPHook(ctx, request, response, err)
 	return response.Response, nil
 }
 
+//SetFallback ...
+func (c *LoadBalancerHTTPClient) SetFallback(fallback Fallback) *LoadBalancerHTTPClient {
+	c.Fallback = fallback
+	return c
+}
+
+//fallback calls the fallback of the request, or the client's, with the final error. The final error is returned
+//if there is no fallback or the fallback fails.
+func (c *LoadBalancerHTTPClient) fallback(ctx context.Context, request *HTTPRequest, err error,
+	history *command.ExecutionHistory) (*http.Response, error) {
+	fallback := request.GetFallback()
+	if fallback == nil {
+		fallback = c.Fallback
+	}
+	if fallback == nil {
+		c.afterHTTPHook(ctx, request, nil, err)
+		return nil, err
+	}
+
+	clientErr, ok := err.(errors.ClientError)
+	if !ok {
+		clientErr = errors.ConvertError(err)
+	}
+	resp, fallbackErr := fallback(ctx, request, clientErr, history)
+	if fallbackErr == nil && resp == nil {
+		fallbackErr = fmt.Errorf("fallback returns no response")
+	}
+	metric.Fallback(ctx, request, clientErr, fallbackErr)
+	if fallbackErr != nil {
+		logger.Warnf(ctx, "err_msg=fallback failed||client=%s||err=%v||fallback_err=%v", c.HTTPClientName, err, fallbackErr)
+		c.afterHTTPHook(ctx, request, nil, err)
+		return nil, err
+	}
+	c.afterHTTPHook(ctx, request, NewHTTPResponse(resp), nil)
+	return resp, nil
+}
+
+//waitForReady blocks until the load balancer is ready if WaitForReady is set, within the deadline of the context,
+//or WaitForReadyTimeout if the context has no deadline.
+func (c *LoadBalancerHTTPClient) waitForReady(ctx context.Context, requestConfig config.ClientConfig) error {
+	cfg := requestConfig
+	if cfg == nil {
+		cfg = c.ClientConfig
+	}
+	if !cfg.GetPropertyAsBool(config.WaitForReady, config.DefaultWaitForReady) {
+		return nil
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.GetPropertyAsDuration(config.WaitForReadyTimeout, config.DefaultWaitForReadyTimeout))
+		defer cancel()
+	}
+	return c.WaitReady(ctx, cfg.GetPropertyAsInteger(config.WaitForReadyMinServers, config.DefaultWaitForReadyMinServers))
+}
+
 //Execute Do not Directly Use...
 func (c *LoadBalancerHTTPClient) Execute(ctx context.Context, request client.Request, requestConfig config.ClientConfig) (client.Response, error) {
 	req, ok := request.(*HTTPRequest)
@@ -135,11 +204,12 @@ func (c *LoadBalancerHTTPClient) ExecuteHTTP(ctx context.Context, request *HTTPR
 		c.ClientConfig.GetPropertyAsDuration(config.ReadWriteTimeout, config.DefaultReadWriteTimeout) ||
 		requestConfig.GetPropertyAsDuration(config.RequestTimeout, config.DefaultRequestTimeout) !=
 		c.ClientConfig.GetPropertyAsDuration(config.RequestTimeout, config.DefaultRequestTimeout)){
-		//create transport
+		//create transport, with the tls config built when the client is created
 		trans := &transport.Transport{
 			ConnectTimeout:   requestConfig.GetPropertyAsDuration(config.ConnectTimeout, config.DefaultConnectTimeout),
 			ReadWriteTimeout: requestConfig.GetPropertyAsDuration(config.ReadWriteTimeout, config.DefaultReadWriteTimeout),
 			RequestTimeout:   requestConfig.GetPropertyAsDuration(config.RequestTimeout, config.DefaultRequestTimeout),
+			TLSClientConfig:  c.tlsConfig,
 		}
 		//create request's http.client
 		requestClient := &http.Client{
@@ -0,0 +1,15 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/loadbalancer/command"
+)
+
+//Fallback is called when the request fails after all the retries, or is rejected by the rate limits, the circuit
+//breakers or the load balancer, e.g. to serve a cached response, a default value or call a secondary service.
+//The history has the attempts on the servers, it is empty if the request never reached a server.
+type Fallback func(ctx context.Context, request *HTTPRequest, err errors.ClientError,
+	history *command.ExecutionHistory) (*http.Response, error)
@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/loadbalancer"
+	"github.com/nienie/marathon/loadbalancer/command"
+	"github.com/nienie/marathon/metric"
+	"github.com/nienie/marathon/server"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fallbackCollector struct {
+	invocations int
+	failures    int
+}
+
+func (c *fallbackCollector) RPC(context.Context, client.Request, client.Response, error, time.Duration) {}
+
+func (c *fallbackCollector) Fallback(ctx context.Context, request client.Request, err error, fallbackErr error) {
+	c.invocations++
+	if fallbackErr != nil {
+		c.failures++
+	}
+}
+
+//TestFallback ...
+func TestFallback(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+	hostPort := strings.Split(upstreamURL.Host, ":")
+	port, _ := strconv.Atoi(hostPort[1])
+
+	collector := &fallbackCollector{}
+	defer metric.SetCollectors(metric.GetCollectors()...)
+	metric.RegisterCollectors(collector)
+
+	clientConfig := config.NewDefaultClientConfig("fallback", nil)
+	lb := loadbalancer.NewBaseLoadBalancer(clientConfig, nil, nil, nil)
+	lb.AddServers([]*server.Server{server.NewServer("http", hostPort[0], port)})
+	httpClient := NewHTTPLoadBalancerClient(clientConfig, lb)
+
+	//no fallback
+	req, _ := NewHTTPRequest(http.MethodGet, "/", nil, nil)
+	resp, err := httpClient.Do(context.Background(), req, nil)
+	assert.Nil(t, resp)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, collector.invocations)
+
+	//the fallback of the client
+	var (
+		fallbackErr     errors.ClientError
+		fallbackHistory *command.ExecutionHistory
+	)
+	httpClient.SetFallback(func(ctx context.Context, request *HTTPRequest, err errors.ClientError,
+		history *command.ExecutionHistory) (*http.Response, error) {
+		fallbackErr = err
+		fallbackHistory = history
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("cached")),
+		}, nil
+	})
+	req, _ = NewHTTPRequest(http.MethodGet, "/", nil, nil)
+	resp, err = httpClient.Do(context.Background(), req, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "cached", string(body))
+	assert.Equal(t, errors.NumberOfRetriesNextServerExceeded, fallbackErr.GetErrType())
+	assert.Equal(t, 2, fallbackHistory.GetAttemptCount())
+	for _, attempt := range fallbackHistory.GetAttempts() {
+		assert.Equal(t, hostPort[0], attempt.Server.GetHost())
+		assert.NotNil(t, attempt.Err)
+	}
+	assert.Equal(t, 1, collector.invocations)
+	assert.Equal(t, 0, collector.failures)
+
+	//the fallback of the request overrides the client's, the final error is returned if it fails
+	req, _ = NewHTTPRequest(http.MethodGet, "/", nil, nil)
+	req.SetFallback(func(ctx context.Context, request *HTTPRequest, err errors.ClientError,
+		history *command.ExecutionHistory) (*http.Response, error) {
+		return nil, fmt.Errorf("secondary service is down")
+	})
+	resp, err = httpClient.Do(context.Background(), req, nil)
+	assert.Nil(t, resp)
+	clientErr, ok := err.(errors.ClientError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.NumberOfRetriesNextServerExceeded, clientErr.GetErrType())
+	assert.Equal(t, 2, collector.invocations)
+	assert.Equal(t, 1, collector.failures)
+
+	//the fallback is called for the requests which never reach a server
+	lb.SetServerList([]*server.Server{})
+	req, _ = NewHTTPRequest(http.MethodGet, "/", nil, nil)
+	resp, err = httpClient.Do(context.Background(), req, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, errors.NoAvailableServer, fallbackErr.GetErrType())
+	assert.Equal(t, 0, fallbackHistory.GetAttemptCount())
+}
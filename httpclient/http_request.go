@@ -14,6 +14,7 @@ type HTTPRequest struct {
 	*http.Request
 	body            []byte
 	loadBalancerKey interface{}
+	fallback        Fallback
 }
 
 //NewHTTPRequest ...
@@ -72,6 +73,17 @@ func (r *HTTPRequest) SetLoadBalancerKey(loadBalancerKey interface{}) *HTTPReque
 	return r
 }
 
+//GetFallback ...
+func (r *HTTPRequest) GetFallback() Fallback {
+	return r.fallback
+}
+
+//SetFallback overrides the fallback of the client for this request.
+func (r *HTTPRequest) SetFallback(fallback Fallback) *HTTPRequest {
+	r.fallback = fallback
+	return r
+}
+
 //GetRawRequest ...
 func (r *HTTPRequest) GetRawRequest() *http.Request {
 	return r.Request
@@ -1,16 +1,23 @@
 package loadbalancer
 
 import (
+	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/loadbalancer/ping"
+	"github.com/nienie/marathon/metric"
 	"github.com/nienie/marathon/server"
 	"github.com/nienie/marathon/utils/timer"
 )
 
+const (
+	minPingTick = 100 * time.Millisecond
+)
+
 //BaseLoadBalancer ...
 type BaseLoadBalancer struct {
 	name string
@@ -20,11 +27,25 @@ type BaseLoadBalancer struct {
 	pingStrategy ping.Strategy
 	lbStats      *Stats
 
-	pingInterval    time.Duration
-	recoverInterval time.Duration
+	pingInterval          time.Duration
+	unhealthyPingInterval time.Duration
+	pingJitter            time.Duration
+	pingRise              int
+	pingFall              int
+	recoverInterval       time.Duration
+
+	nextPingTimeLock *sync.Mutex
+	nextPingTime     map[string]time.Time
+
+	drainLock          *sync.Mutex
+	drainedServers     map[string]bool
+	maintenanceWindows map[string][]MaintenanceWindow
 
+	listenerLock          *sync.RWMutex
 	changeListeners       []server.ListChangeListener
 	serverStatusListeners []server.StatusChangeListener
+	eventListeners        []registeredEventListener
+	eventListenerID       uint64
 
 	allServerLock      *sync.RWMutex
 	upServerLock       *sync.RWMutex
@@ -33,8 +54,9 @@ type BaseLoadBalancer struct {
 	upServersList      []*server.Server
 	tempDownServerList []*server.Server
 
-	faultRecoverTimer *timer.Timer
-	healthCheckTimer  *timer.Timer
+	faultRecoverTimer   *timer.Timer
+	healthCheckTimer    *timer.Timer
+	healthCheckRegistry *HealthCheckRegistry
 
 	pingInProgress    int32
 	recoverInProgress int32
@@ -48,9 +70,20 @@ func NewBaseLoadBalancer(clientConfig config.ClientConfig, rule Rule, pingAction
 		pingAction:            pingAction,
 		pingStrategy:          pingStrategy,
 		pingInterval:          clientConfig.GetPropertyAsDuration(config.PingInterval, config.DefaultPingInterval),
+		unhealthyPingInterval: clientConfig.GetPropertyAsDuration(config.PingUnhealthyInterval, config.DefaultPingUnhealthyInterval),
+		pingJitter:            clientConfig.GetPropertyAsDuration(config.PingJitter, config.DefaultPingJitter),
+		pingRise:              clientConfig.GetPropertyAsInteger(config.PingRise, config.DefaultPingRise),
+		pingFall:              clientConfig.GetPropertyAsInteger(config.PingFall, config.DefaultPingFall),
 		recoverInterval:       time.Second * 1,
+		nextPingTimeLock:      &sync.Mutex{},
+		nextPingTime:          make(map[string]time.Time),
+		drainLock:             &sync.Mutex{},
+		drainedServers:        make(map[string]bool),
+		maintenanceWindows:    make(map[string][]MaintenanceWindow),
+		listenerLock:          &sync.RWMutex{},
 		changeListeners:       make([]server.ListChangeListener, 0),
 		serverStatusListeners: make([]server.StatusChangeListener, 0),
+		eventListeners:        make([]registeredEventListener, 0),
 		allServersList:        make([]*server.Server, 0, 20),
 		upServersList:         make([]*server.Server, 0, 20),
 		tempDownServerList:    make([]*server.Server, 0, 20),
@@ -61,6 +94,9 @@ func NewBaseLoadBalancer(clientConfig config.ClientConfig, rule Rule, pingAction
 	if loadBalancer.pingStrategy == nil {
 		loadBalancer.pingStrategy = ping.NewParallelStrategy()
 	}
+	if clientConfig.GetPropertyAsBool(config.PingShared, config.DefaultPingShared) {
+		loadBalancer.healthCheckRegistry = GetDefaultHealthCheckRegistry()
+	}
 	loadBalancer.lbStats = NewLoadBalancerStats(clientConfig)
 	loadBalancer.SetRule(rule)
 	loadBalancer.setupPingTask()
@@ -120,15 +156,122 @@ func (o *BaseLoadBalancer)GetPingStrategy() ping.Strategy {
 	return o.pingStrategy
 }
 
+//SetHealthCheckRegistry shares the health checks with the other load balancers through the registry,
+//nil makes the load balancer run its own health checks.
+func (o *BaseLoadBalancer) SetHealthCheckRegistry(registry *HealthCheckRegistry) {
+	o.stopPingTask()
+	o.healthCheckRegistry = registry
+	o.setupPingTask()
+}
+
+//GetHealthCheckRegistry ...
+func (o *BaseLoadBalancer) GetHealthCheckRegistry() *HealthCheckRegistry {
+	return o.healthCheckRegistry
+}
+
 func (o *BaseLoadBalancer) setupPingTask() {
 	if o.healthCheckTimer != nil {
 		o.healthCheckTimer.Cancel()
 	}
+	if o.healthCheckRegistry != nil {
+		o.subscribeHealthChecks()
+		return
+	}
 	o.healthCheckTimer = timer.NewTimer(o.name + "_HealthCheckTask")
-	o.healthCheckTimer.Schedule(o.runPingTask, o.pingInterval, 0)
+	o.healthCheckTimer.Schedule(o.runPingTask, o.getPingTick(), 0)
 	o.runPingTask()
 }
 
+//getPingTick the period to check which servers are due to be pinged, every server has its own schedule.
+func (o *BaseLoadBalancer) getPingTick() time.Duration {
+	tick := o.pingInterval
+	if o.unhealthyPingInterval > 0 && o.unhealthyPingInterval < tick {
+		tick = o.unhealthyPingInterval
+	}
+	if o.pingJitter > 0 {
+		if o.pingJitter < tick {
+			tick = o.pingJitter
+		}
+		tick = tick / 2
+	}
+	if tick < minPingTick {
+		tick = minPingTick
+	}
+	return tick
+}
+
+//scheduleNextPing schedules the next ping of the server, a faster interval is used while the server is down,
+//and a random jitter is added so that the servers are not pinged at the same moment.
+func (o *BaseLoadBalancer) scheduleNextPing(svr *server.Server, now time.Time) time.Time {
+	interval := o.pingInterval
+	if !svr.IsAlive() && o.unhealthyPingInterval > 0 {
+		interval = o.unhealthyPingInterval
+	}
+	if o.pingJitter > 0 {
+		interval += time.Duration(rand.Int63n(int64(o.pingJitter)))
+	}
+	return now.Add(interval)
+}
+
+//getServersToPing returns the servers which are due to be pinged, the schedules of the removed servers are dropped.
+func (o *BaseLoadBalancer) getServersToPing(allServers []*server.Server, now time.Time) []*server.Server {
+	o.nextPingTimeLock.Lock()
+	defer o.nextPingTimeLock.Unlock()
+	servers := make([]*server.Server, 0, len(allServers))
+	nextPingTime := make(map[string]time.Time, len(allServers))
+	for _, svr := range allServers {
+		key := getServerKey(svr)
+		next, ok := o.nextPingTime[key]
+		if !ok || !now.Before(next) {
+			servers = append(servers, svr)
+			continue
+		}
+		nextPingTime[key] = next
+	}
+	o.nextPingTime = nextPingTime
+	return servers
+}
+
+//applyPingResult decides the new alive state of the server with the rise/fall thresholds.
+func (o *BaseLoadBalancer) applyPingResult(svr *server.Server, result *ping.Result, now time.Time) bool {
+	isAlive := result.Alive
+	history := o.lbStats.GetSingleServerStats(svr).GetHealthCheckHistory()
+	history.Add(server.HealthCheckResult{
+		Timestamp: now,
+		Alive:     isAlive,
+		Latency:   result.Latency,
+	})
+	metric.HealthCheck(o.name, svr, isAlive, result.Latency)
+
+	oldState := svr.GetState()
+	oldIsAlive := oldState.IsAlive()
+	switch {
+	case !isAlive && (oldState == server.StateWarming || oldIsAlive && history.GetConsecutiveFailures() >= o.pingFall):
+		svr.Transition(server.StateDown, "health check failed")
+	case isAlive && !oldIsAlive && history.GetConsecutiveSuccesses() >= o.pingRise:
+		svr.Transition(o.getRecoveredState(svr, now), "health check passed")
+	case isAlive && !oldIsAlive:
+		svr.Transition(server.StateWarming, "health check passed")
+	case isAlive && oldState == server.StateUnknown:
+		svr.Transition(server.StateUp, "health check passed")
+	}
+	newIsAlive := svr.IsAlive()
+
+	o.nextPingTimeLock.Lock()
+	o.nextPingTime[getServerKey(svr)] = o.scheduleNextPing(svr, now)
+	o.nextPingTimeLock.Unlock()
+	return newIsAlive
+}
+
+//GetServerHealthCheckHistory returns the recent health check results of the server, from the oldest to the newest.
+func (o *BaseLoadBalancer) GetServerHealthCheckHistory(svr *server.Server) []server.HealthCheckResult {
+	stats := o.lbStats.GetSingleServerStats(svr)
+	if stats == nil {
+		return nil
+	}
+	return stats.GetHealthCheckHistory().GetResults()
+}
+
 func (o *BaseLoadBalancer) runPingTask() {
 	if !atomic.CompareAndSwapInt32(&o.pingInProgress, 0, 1) {
 		return
@@ -140,38 +283,94 @@ func (o *BaseLoadBalancer) runPingTask() {
 	allServers := server.CloneServerList(o.allServersList)
 	o.allServerLock.RUnlock()
 
-	numCandidates := len(allServers)
-	newUpList := make([]*server.Server, 0)
 	changeServers := make([]*server.Server, 0)
-	pingResults := o.pingStrategy.PingServers(o.pingAction, allServers)
+	pingAction := o.pingAction
+	if pingAction == nil {
+		//no health check, all the servers are considered alive
+		pingAction = &ping.NoOpPing{}
+	}
+	now := time.Now()
+	dueServers := o.getServersToPing(allServers, now)
+	pingResults := pingServers(o.pingStrategy, pingAction, dueServers)
 
-	for i := 0; i < numCandidates; i++ {
-		isAlive := pingResults[i]
-		svr := allServers[i]
+	for i, svr := range dueServers {
 		oldIsAlive := svr.IsAlive()
-
-		svr.SetAlive(isAlive)
+		isAlive := o.applyPingResult(svr, pingResults[i], now)
 		if oldIsAlive != isAlive {
 			changeServers = append(changeServers, svr)
 		}
-
 		if isAlive {
+			o.restoreServer(svr, "health check passed")
+		}
+	}
+
+	o.refreshUpServerList()
+	o.notifyServerStatusChangeListener(changeServers, "health check")
+}
+
+//pingServers pings the servers with the strategy, the latency is only known if the strategy is a ping.ResultStrategy.
+func pingServers(strategy ping.Strategy, pingAction ping.Ping, servers []*server.Server) []*ping.Result {
+	if resultStrategy, ok := strategy.(ping.ResultStrategy); ok {
+		return resultStrategy.PingServersWithResults(pingAction, servers)
+	}
+	aliveResults := strategy.PingServers(pingAction, servers)
+	results := make([]*ping.Result, len(aliveResults))
+	for i, isAlive := range aliveResults {
+		results[i] = &ping.Result{Alive: isAlive}
+	}
+	return results
+}
+
+//subscribeHealthChecks subscribes all the servers to the shared health checks and runs the new ones at once,
+//the others, including the ones of the other load balancers, are left to the task of the registry.
+func (o *BaseLoadBalancer) subscribeHealthChecks() {
+	pingAction := o.pingAction
+	if pingAction == nil {
+		pingAction = &ping.NoOpPing{}
+	}
+	newProbes := o.healthCheckRegistry.subscribe(o, pingAction, o.pingStrategy, o.GetAllServers())
+	o.healthCheckRegistry.runProbes(newProbes, time.Now())
+}
+
+//onHealthCheck applies the result of a shared health check, and returns when the server should be checked next.
+func (o *BaseLoadBalancer) onHealthCheck(svr *server.Server, result *ping.Result, now time.Time) time.Time {
+	oldIsAlive := svr.IsAlive()
+	isAlive := o.applyPingResult(svr, result, now)
+	if isAlive {
+		o.restoreServer(svr, "health check passed")
+	}
+	if oldIsAlive != isAlive {
+		o.refreshUpServerList()
+		o.notifyServerStatusChangeListener([]*server.Server{svr}, "health check")
+	}
+	o.nextPingTimeLock.Lock()
+	defer o.nextPingTimeLock.Unlock()
+	return o.nextPingTime[getServerKey(svr)]
+}
+
+//refreshUpServerList rebuilds the up list with the alive servers.
+func (o *BaseLoadBalancer) refreshUpServerList() {
+	o.allServerLock.RLock()
+	newUpList := make([]*server.Server, 0, len(o.allServersList))
+	for _, svr := range o.allServersList {
+		if svr.IsAlive() {
 			newUpList = append(newUpList, svr)
-			svr.SetTempDown(false)
 		}
 	}
+	o.allServerLock.RUnlock()
 
 	o.upServerLock.Lock()
 	o.upServersList = newUpList
 	o.upServerLock.Unlock()
-
-	o.notifyServerStatusChangeListener(changeServers)
 }
 
 func (o *BaseLoadBalancer) stopPingTask() {
 	if o.healthCheckTimer != nil {
 		o.healthCheckTimer.Cancel()
 	}
+	if o.healthCheckRegistry != nil {
+		o.healthCheckRegistry.unsubscribe(o)
+	}
 }
 
 func (o *BaseLoadBalancer) setupFaultRecoverTask() {
@@ -192,23 +391,25 @@ func (o *BaseLoadBalancer) runFaultRecoverTask() {
 	newTempDownServers := make([]*server.Server, 0)
 	currentTime := time.Duration(time.Now().UnixNano())
 	o.tempDownServerLock.Lock()
+	recoveredServers := make([]*server.Server, 0)
 	for _, svr := range o.tempDownServerList {
 		if svr.IsTempDown() == false {
 			continue
 		}
-		if svr.IsAlive() == false {
-			svr.SetTempDown(false)
-			continue
-		}
 		stats := o.lbStats.GetSingleServerStats(svr)
 		if !stats.IsCircuitBreakerTripped(currentTime) {
-			svr.SetTempDown(false)
+			recoveredServers = append(recoveredServers, svr)
 			continue
 		}
 		newTempDownServers = append(newTempDownServers, svr)
 	}
 	o.tempDownServerList = newTempDownServers
 	o.tempDownServerLock.Unlock()
+
+	for _, svr := range recoveredServers {
+		o.restoreServer(svr, "circuit breaker timeout")
+	}
+	o.refreshDraining()
 }
 
 func (o *BaseLoadBalancer) stopFaultRecoverTask() {
@@ -217,12 +418,84 @@ func (o *BaseLoadBalancer) stopFaultRecoverTask() {
 	}
 }
 
-func (o *BaseLoadBalancer) notifyServerStatusChangeListener(changeServes []*server.Server) {
-	if changeServes != nil && len(changeServes) != 0 && o.serverStatusListeners != nil {
-		for _, serverStatusChangeListener := range o.serverStatusListeners {
-			serverStatusChangeListener.ServerStatusChanged(changeServes)
+func (o *BaseLoadBalancer) notifyServerStatusChangeListener(changeServes []*server.Server, reason string) {
+	if len(changeServes) == 0 {
+		return
+	}
+	o.listenerLock.RLock()
+	serverStatusListeners := o.serverStatusListeners
+	o.listenerLock.RUnlock()
+	for _, serverStatusChangeListener := range serverStatusListeners {
+		serverStatusChangeListener.ServerStatusChanged(changeServes)
+	}
+	for _, svr := range changeServes {
+		if svr.IsAlive() {
+			o.notifyEvent(NewEvent(EventServerUp, o.name, svr, reason))
+		} else {
+			o.notifyEvent(NewEvent(EventServerDown, o.name, svr, reason))
+		}
+	}
+}
+
+func (o *BaseLoadBalancer) notifyEvent(event *Event) {
+	o.listenerLock.RLock()
+	eventListeners := o.eventListeners
+	o.listenerLock.RUnlock()
+	for _, eventListener := range eventListeners {
+		eventListener.listener.OnEvent(event)
+	}
+}
+
+//AddServerListChangeListener ...
+func (o *BaseLoadBalancer) AddServerListChangeListener(listener server.ListChangeListener) {
+	if listener == nil {
+		return
+	}
+	o.listenerLock.Lock()
+	defer o.listenerLock.Unlock()
+	changeListeners := make([]server.ListChangeListener, len(o.changeListeners), len(o.changeListeners)+1)
+	copy(changeListeners, o.changeListeners)
+	o.changeListeners = append(changeListeners, listener)
+}
+
+//AddServerStatusChangeListener ...
+func (o *BaseLoadBalancer) AddServerStatusChangeListener(listener server.StatusChangeListener) {
+	if listener == nil {
+		return
+	}
+	o.listenerLock.Lock()
+	defer o.listenerLock.Unlock()
+	serverStatusListeners := make([]server.StatusChangeListener, len(o.serverStatusListeners), len(o.serverStatusListeners)+1)
+	copy(serverStatusListeners, o.serverStatusListeners)
+	o.serverStatusListeners = append(serverStatusListeners, listener)
+}
+
+//AddEventListener registers the listener, the returned id removes it, a nil listener gets the id 0.
+func (o *BaseLoadBalancer) AddEventListener(listener EventListener) EventListenerID {
+	if listener == nil {
+		return 0
+	}
+	o.listenerLock.Lock()
+	defer o.listenerLock.Unlock()
+	o.eventListenerID++
+	id := EventListenerID(o.eventListenerID)
+	eventListeners := make([]registeredEventListener, len(o.eventListeners), len(o.eventListeners)+1)
+	copy(eventListeners, o.eventListeners)
+	o.eventListeners = append(eventListeners, registeredEventListener{id: id, listener: listener})
+	return id
+}
+
+//RemoveEventListener ...
+func (o *BaseLoadBalancer) RemoveEventListener(id EventListenerID) {
+	o.listenerLock.Lock()
+	defer o.listenerLock.Unlock()
+	eventListeners := make([]registeredEventListener, 0, len(o.eventListeners))
+	for _, l := range o.eventListeners {
+		if l.id != id {
+			eventListeners = append(eventListeners, l)
 		}
 	}
+	o.eventListeners = eventListeners
 }
 
 //SetPingInterval ...
@@ -269,17 +542,17 @@ func (o *BaseLoadBalancer) SetServerList(servers []*server.Server) {
 		allServers = append(allServers, svr)
 	}
 
-	if !server.CompareServerList(o.allServersList, allServers) {
+	o.allServerLock.RLock()
+	oldServers := o.allServersList
+	o.allServerLock.RUnlock()
+	if !server.CompareServerList(oldServers, allServers) {
 		listChanged = true
-		if o.changeListeners != nil && len(o.changeListeners) > 0 {
-			oldList := server.CloneServerList(o.allServersList)
-			newList := server.CloneServerList(allServers)
-			o.notifyServerListChanged(oldList, newList)
-		}
+		o.notifyServerListChanged(server.CloneServerList(oldServers), server.CloneServerList(allServers))
 	}
 	o.allServerLock.Lock()
 	o.allServersList = allServers
 	o.allServerLock.Unlock()
+	o.refreshDraining()
 
 	o.tempDownServerLock.Lock()
 	o.tempDownServerList = make([]*server.Server, 0)
@@ -303,9 +576,32 @@ func (o *BaseLoadBalancer) SetServerList(servers []*server.Server) {
 }
 
 func (o *BaseLoadBalancer) notifyServerListChanged(oldList, newList []*server.Server) {
-	for _, serverListChangedListener := range o.changeListeners {
+	o.listenerLock.RLock()
+	changeListeners := o.changeListeners
+	o.listenerLock.RUnlock()
+	for _, serverListChangedListener := range changeListeners {
 		serverListChangedListener.ServerListChanged(oldList, newList)
 	}
+
+	oldServers := make(map[string]bool, len(oldList))
+	for _, svr := range oldList {
+		oldServers[getServerKey(svr)] = true
+	}
+	newServers := make(map[string]bool, len(newList))
+	for _, svr := range newList {
+		key := getServerKey(svr)
+		if !oldServers[key] && !newServers[key] {
+			o.notifyEvent(NewEvent(EventServerAdded, o.name, svr, "server list changed"))
+		}
+		newServers[key] = true
+	}
+	for _, svr := range oldList {
+		key := getServerKey(svr)
+		if !newServers[key] {
+			newServers[key] = true //a duplicated server is reported once
+			o.notifyEvent(NewEvent(EventServerRemoved, o.name, svr, "server list changed"))
+		}
+	}
 }
 
 //SetServerListForClusters ...
@@ -334,13 +630,23 @@ func (o *BaseLoadBalancer) ChooseServer(key interface{}) *server.Server {
 	return o.rule.Choose(key)
 }
 
+//ChooseServerWithContext passes the context to the rule if it is a ContextRule.
+func (o *BaseLoadBalancer) ChooseServerWithContext(ctx context.Context, key interface{}) *server.Server {
+	if o.rule == nil {
+		return nil
+	}
+	if rule, ok := o.rule.(ContextRule); ok {
+		return rule.ChooseWithContext(ctx, key)
+	}
+	return o.rule.Choose(key)
+}
+
 //MarkServerDown ...
 func (o *BaseLoadBalancer) MarkServerDown(svr *server.Server) {
-	if svr == nil || svr.IsAlive() == false {
+	if svr == nil || svr.IsAlive() == false || svr.Transition(server.StateDown, "marked down") != nil {
 		return
 	}
-	svr.SetAlive(false)
-	o.notifyServerStatusChangeListener([]*server.Server{svr})
+	o.notifyServerStatusChangeListener([]*server.Server{svr}, "marked down")
 }
 
 //GetReachableServers ...
@@ -349,7 +655,7 @@ func (o *BaseLoadBalancer) GetReachableServers() []*server.Server {
 	o.upServerLock.RLock()
 	defer o.upServerLock.RUnlock()
 	for _, svr := range o.upServersList {
-		if svr.IsAlive() && !svr.IsTempDown() {
+		if svr.GetState().IsAvailable() {
 			reachableServers = append(reachableServers, svr)
 		}
 	}
@@ -370,21 +676,54 @@ func (o *BaseLoadBalancer) GetLoadBalancerStats() *Stats {
 
 //MarkServerTempDown ...
 func (o *BaseLoadBalancer) MarkServerTempDown(svr *server.Server) {
-	if svr == nil || svr.IsAlive() == false || svr.IsTempDown() == true {
+	if svr == nil {
+		return
+	}
+	stats := o.lbStats.GetSingleServerStats(svr)
+	isTripped := stats != nil && stats.IsCircuitBreakerTripped(time.Duration(time.Now().UnixNano()))
+	state, reason := server.StateTempDown, "marked temp down"
+	if isTripped {
+		state, reason = server.StateEjected, "circuit breaker tripped"
+	}
+	if !svr.TransitionFrom([]server.State{server.StateUnknown, server.StateUp}, state, reason) {
 		return
 	}
-	svr.SetTempDown(true)
 	o.tempDownServerLock.Lock()
 	o.tempDownServerList = append(o.tempDownServerList, svr)
 	o.tempDownServerLock.Unlock()
+
+	if isTripped {
+		o.notifyEvent(NewEvent(EventCircuitOpened, o.name, svr, reason))
+	}
+	o.notifyEvent(NewEvent(EventServerEjected, o.name, svr, reason))
 }
 
 //MarkServerReady ...
 func (o *BaseLoadBalancer) MarkServerReady(svr *server.Server) {
-	if svr == nil || svr.IsAlive() == false || svr.IsTempDown() == false {
+	if svr == nil {
+		return
+	}
+	o.restoreServer(svr, "marked ready")
+}
+
+//restoreServer puts the temporarily down or ejected server back into rotation, or drains it if it is requested.
+func (o *BaseLoadBalancer) restoreServer(svr *server.Server, reason string) {
+	oldState := svr.GetState()
+	if oldState != server.StateTempDown && oldState != server.StateEjected {
+		return
+	}
+	newState := o.getRecoveredState(svr, time.Now())
+	if !svr.TransitionFrom([]server.State{oldState}, newState, reason) {
+		return
+	}
+	if oldState == server.StateEjected {
+		o.notifyEvent(NewEvent(EventCircuitClosed, o.name, svr, reason))
+	}
+	if newState == server.StateDraining {
+		o.notifyEvent(NewEvent(EventServerDraining, o.name, svr, reason))
 		return
 	}
-	svr.SetTempDown(false)
+	o.notifyEvent(NewEvent(EventServerRestored, o.name, svr, reason))
 }
 
 //Shutdown ...
@@ -0,0 +1,188 @@
+package loadbalancer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nienie/marathon/server"
+)
+
+const (
+	drainCheckInterval = 100 * time.Millisecond
+)
+
+var (
+	drainableStates = []server.State{server.StateUnknown, server.StateUp, server.StateTempDown, server.StateEjected}
+)
+
+//MaintenanceWindow the servers are drained from Start until End.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+//Contains ...
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+//getServerKey identifies a server across the server list updates, the discovery creates new instances every time.
+func getServerKey(svr *server.Server) string {
+	return svr.GetScheme() + "://" + svr.GetHostPort()
+}
+
+//Drain stops sending new requests to the server, the in-flight ones finish normally.
+//The server may not be in the server list yet, e.g. it is drained ahead of a deployment.
+func (o *BaseLoadBalancer) Drain(svr *server.Server) {
+	if svr == nil {
+		return
+	}
+	o.drainLock.Lock()
+	o.drainedServers[getServerKey(svr)] = true
+	o.drainLock.Unlock()
+	o.refreshDraining()
+}
+
+//Undrain puts the drained server back into rotation, a maintenance window or a drain directive from
+//the discovery still keeps it draining.
+func (o *BaseLoadBalancer) Undrain(svr *server.Server) {
+	if svr == nil {
+		return
+	}
+	o.drainLock.Lock()
+	delete(o.drainedServers, getServerKey(svr))
+	o.drainLock.Unlock()
+	o.refreshDraining()
+}
+
+//ScheduleMaintenance drains the server during the window.
+func (o *BaseLoadBalancer) ScheduleMaintenance(svr *server.Server, window MaintenanceWindow) {
+	if svr == nil || !window.Start.Before(window.End) {
+		return
+	}
+	key := getServerKey(svr)
+	o.drainLock.Lock()
+	o.maintenanceWindows[key] = append(o.maintenanceWindows[key], window)
+	o.drainLock.Unlock()
+	o.refreshDraining()
+}
+
+//CancelMaintenance drops all the maintenance windows of the server.
+func (o *BaseLoadBalancer) CancelMaintenance(svr *server.Server) {
+	if svr == nil {
+		return
+	}
+	o.drainLock.Lock()
+	delete(o.maintenanceWindows, getServerKey(svr))
+	o.drainLock.Unlock()
+	o.refreshDraining()
+}
+
+//GetMaintenanceWindows returns the maintenance windows of the server which have not ended.
+func (o *BaseLoadBalancer) GetMaintenanceWindows(svr *server.Server) []MaintenanceWindow {
+	o.drainLock.Lock()
+	defer o.drainLock.Unlock()
+	windows := o.maintenanceWindows[getServerKey(svr)]
+	ret := make([]MaintenanceWindow, len(windows))
+	copy(ret, windows)
+	return ret
+}
+
+//WaitDrained waits until the server has no active requests or the timeout expires, it returns whether
+//the server is drained.
+func (o *BaseLoadBalancer) WaitDrained(svr *server.Server, timeout time.Duration) bool {
+	stats := o.lbStats.GetSingleServerStats(svr)
+	if stats == nil {
+		return true
+	}
+	isDrained := func() bool {
+		return stats.GetActiveRequestsCount(time.Duration(time.Now().UnixNano())) <= 0
+	}
+	if isDrained() {
+		return true
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if isDrained() {
+				return true
+			}
+		case <-deadline.C:
+			return isDrained()
+		}
+	}
+}
+
+//getDrainReason returns why the server should be draining, empty if it should not.
+func (o *BaseLoadBalancer) getDrainReason(svr *server.Server, now time.Time) string {
+	key := getServerKey(svr)
+	if o.drainedServers[key] {
+		return "drained"
+	}
+	for _, window := range o.maintenanceWindows[key] {
+		if window.Contains(now) {
+			return "maintenance window"
+		}
+	}
+	if val, ok := svr.GetMetadata(server.DrainMetadataKey); ok {
+		if drain, err := strconv.ParseBool(val); err == nil && drain {
+			return "drain directive from discovery"
+		}
+	}
+	return ""
+}
+
+//getRecoveredState returns the state of a server which comes back into rotation, draining if it is requested.
+func (o *BaseLoadBalancer) getRecoveredState(svr *server.Server, now time.Time) server.State {
+	o.drainLock.Lock()
+	defer o.drainLock.Unlock()
+	if o.getDrainReason(svr, now) != "" {
+		return server.StateDraining
+	}
+	return server.StateUp
+}
+
+//refreshDraining applies the drain requests, the maintenance windows and the drain directives to the servers,
+//the ended maintenance windows are dropped.
+func (o *BaseLoadBalancer) refreshDraining() {
+	now := time.Now()
+	events := make([]*Event, 0)
+
+	o.drainLock.Lock()
+	for key, windows := range o.maintenanceWindows {
+		activeWindows := make([]MaintenanceWindow, 0, len(windows))
+		for _, window := range windows {
+			if now.Before(window.End) {
+				activeWindows = append(activeWindows, window)
+			}
+		}
+		if len(activeWindows) == 0 {
+			delete(o.maintenanceWindows, key)
+			continue
+		}
+		o.maintenanceWindows[key] = activeWindows
+	}
+	for _, svr := range o.GetAllServers() {
+		reason := o.getDrainReason(svr, now)
+		if reason != "" {
+			//a down server is drained when it recovers
+			if svr.TransitionFrom(drainableStates, server.StateDraining, reason) {
+				events = append(events, NewEvent(EventServerDraining, o.name, svr, reason))
+			}
+			continue
+		}
+		if svr.TransitionFrom([]server.State{server.StateDraining}, server.StateUp, "undrained") {
+			events = append(events, NewEvent(EventServerRestored, o.name, svr, "undrained"))
+		}
+	}
+	o.drainLock.Unlock()
+
+	for _, event := range events {
+		o.notifyEvent(event)
+	}
+}
@@ -0,0 +1,69 @@
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestBaseLoadBalancerDrain ...
+func TestBaseLoadBalancerDrain(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, nil, nil)
+	defer lb.Shutdown()
+	svr1 := server.NewServer("http", "127.0.0.1", 8080)
+	svr2 := server.NewServer("http", "127.0.0.1", 8081)
+	lb.AddServers([]*server.Server{svr1, svr2})
+	events, cancel := SubscribeEvents(lb, 16)
+	defer cancel()
+
+	lb.Drain(svr1)
+	assert.True(t, svr1.IsDraining())
+	assert.Equal(t, []*server.Server{svr2}, lb.GetReachableServers())
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, svr2, lb.ChooseServer(nil))
+	}
+	assert.Equal(t, []EventType{EventServerDraining}, drainEvents(events))
+
+	//the drain survives the server list updates from the discovery
+	newSvr1 := server.NewServer("http", "127.0.0.1", 8080)
+	lb.SetServerList([]*server.Server{newSvr1, svr2})
+	assert.True(t, newSvr1.IsDraining())
+
+	stats := lb.GetLoadBalancerStats().GetSingleServerStats(newSvr1)
+	stats.IncrementActiveRequestsCount()
+	assert.False(t, lb.WaitDrained(newSvr1, 150*time.Millisecond))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		stats.DecrementActiveRequestsCount()
+	}()
+	assert.True(t, lb.WaitDrained(newSvr1, time.Second))
+
+	lb.Undrain(newSvr1)
+	assert.False(t, newSvr1.IsDraining())
+	assert.Equal(t, 2, len(lb.GetReachableServers()))
+}
+
+//TestBaseLoadBalancerMaintenanceWindow ...
+func TestBaseLoadBalancerMaintenanceWindow(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, nil, nil)
+	defer lb.Shutdown()
+	svr1 := server.NewServer("http", "127.0.0.1", 8080)
+	svr2 := server.NewServer("http", "127.0.0.1", 8081).SetMetadata(server.DrainMetadataKey, "true")
+	lb.AddServers([]*server.Server{svr1, svr2})
+	assert.True(t, svr2.IsDraining())
+
+	now := time.Now()
+	lb.ScheduleMaintenance(svr1, MaintenanceWindow{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)})
+	assert.False(t, svr1.IsDraining())
+	lb.ScheduleMaintenance(svr1, MaintenanceWindow{Start: now.Add(-time.Minute), End: now.Add(time.Hour)})
+	assert.True(t, svr1.IsDraining())
+	assert.Equal(t, 2, len(lb.GetMaintenanceWindows(svr1)))
+	assert.Equal(t, 0, len(lb.GetReachableServers()))
+
+	lb.CancelMaintenance(svr1)
+	assert.False(t, svr1.IsDraining())
+	assert.Equal(t, []*server.Server{svr1}, lb.GetReachableServers())
+}
@@ -0,0 +1,131 @@
+package loadbalancer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type scriptedPing struct {
+	sync.Mutex
+	alive bool
+}
+
+func (p *scriptedPing) IsAlive(svr *server.Server) bool {
+	p.Lock()
+	defer p.Unlock()
+	return p.alive
+}
+
+func (p *scriptedPing) setAlive(alive bool) {
+	p.Lock()
+	p.alive = alive
+	p.Unlock()
+}
+
+//pingNow pings all the servers regardless of their schedules.
+func pingNow(lb *BaseLoadBalancer) {
+	lb.nextPingTimeLock.Lock()
+	lb.nextPingTime = make(map[string]time.Time)
+	lb.nextPingTimeLock.Unlock()
+	lb.runPingTask()
+}
+
+//TestBaseLoadBalancerPingRiseFall ...
+func TestBaseLoadBalancerPingRiseFall(t *testing.T) {
+	clientConfig := config.NewDefaultClientConfig("test", nil)
+	clientConfig.SetProperty(config.PingRise, 2)
+	clientConfig.SetProperty(config.PingFall, 3)
+	pingAction := &scriptedPing{alive: true}
+	lb := NewBaseLoadBalancer(clientConfig, nil, pingAction, nil)
+	defer lb.Shutdown()
+
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	lb.AddServers([]*server.Server{svr})
+	pingNow(lb)
+	assert.Equal(t, 1, len(lb.GetReachableServers()))
+
+	pingAction.setAlive(false)
+	pingNow(lb)
+	pingNow(lb)
+	assert.Equal(t, true, svr.IsAlive())
+	pingNow(lb)
+	assert.Equal(t, false, svr.IsAlive())
+	assert.Equal(t, 0, len(lb.GetReachableServers()))
+
+	pingAction.setAlive(true)
+	pingNow(lb)
+	assert.Equal(t, false, svr.IsAlive())
+	pingNow(lb)
+	assert.Equal(t, true, svr.IsAlive())
+	assert.Equal(t, 1, len(lb.GetReachableServers()))
+
+	history := lb.GetServerHealthCheckHistory(svr)
+	assert.True(t, len(history) >= 5)
+	assert.Equal(t, true, history[len(history)-1].Alive)
+}
+
+//freshServerList creates new server instances on every call like the discovery does.
+type freshServerList struct{}
+
+func (l *freshServerList) GetInitialListOfServers() []*server.Server {
+	return l.GetUpdatedListOfServers()
+}
+
+func (l *freshServerList) GetUpdatedListOfServers() []*server.Server {
+	return []*server.Server{server.NewServer("http", "127.0.0.1", 8080)}
+}
+
+//TestServerListUpdateKeepsHealthState ...
+func TestServerListUpdateKeepsHealthState(t *testing.T) {
+	clientConfig := config.NewDefaultClientConfig("test", nil)
+	clientConfig.SetProperty(config.PingRise, 3)
+	clientConfig.SetProperty(config.PingFall, 1)
+	lb := NewDynamicServerListLoadBalancerWithUpdater(clientConfig, nil, &freshServerList{}, nil)
+	defer lb.Shutdown()
+	pingAction := &scriptedPing{alive: false}
+	lb.SetPing(pingAction)
+
+	pingNow(lb.BaseLoadBalancer)
+	assert.Equal(t, false, lb.GetAllServers()[0].IsAlive())
+
+	lb.UpdateListOfServers()
+	svr := lb.GetAllServers()[0]
+	assert.Equal(t, server.StateDown, svr.GetState())
+	assert.Equal(t, 0, len(lb.GetReachableServers()))
+	lb.nextPingTimeLock.Lock()
+	_, scheduled := lb.nextPingTime[getServerKey(svr)]
+	lb.nextPingTimeLock.Unlock()
+	assert.Equal(t, true, scheduled)
+
+	pingAction.setAlive(true)
+	pingNow(lb.BaseLoadBalancer)
+	lb.UpdateListOfServers()
+	pingNow(lb.BaseLoadBalancer)
+	lb.UpdateListOfServers()
+	assert.Equal(t, server.StateWarming, lb.GetAllServers()[0].GetState())
+	pingNow(lb.BaseLoadBalancer)
+	svr = lb.GetAllServers()[0]
+	assert.Equal(t, true, svr.IsAlive())
+	assert.Equal(t, 1, len(lb.GetReachableServers()))
+	assert.True(t, len(lb.GetServerHealthCheckHistory(svr)) >= 4)
+}
+
+//TestInvalidServerListFilters ...
+func TestInvalidServerListFilters(t *testing.T) {
+	clientConfig := config.NewDefaultClientConfig("test", nil)
+	clientConfig.SetProperty(config.ServerListFilters, "deny=127.0.0.1")
+	lb := NewDynamicServerListLoadBalancerWithUpdater(clientConfig, nil, &freshServerList{}, nil)
+	defer lb.Shutdown()
+	assert.Equal(t, 0, len(lb.GetAllServers()))
+	assert.Nil(t, lb.ChooseServer(nil))
+
+	clientConfig.SetProperty(config.ServerListFilters, "deny:127.0.0.2")
+	lb = NewDynamicServerListLoadBalancerWithUpdater(clientConfig, nil, &freshServerList{}, nil)
+	defer lb.Shutdown()
+	assert.Equal(t, 1, len(lb.GetAllServers()))
+}
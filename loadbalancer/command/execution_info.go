@@ -1,6 +1,8 @@
 package command
 
 import (
+	"time"
+
 	"github.com/nienie/marathon/server"
 )
 
@@ -67,3 +69,38 @@ func (o *ExecutionInfoContext) ToFinalExecutionInfo() *ExecutionInfo {
 		NumberOfPastServersAttempted: o.serverAttemptCount - 1,
 	}
 }
+
+//Attempt an execution of the request on a server, including the retries.
+type Attempt struct {
+	Server    *server.Server
+	Err       error
+	Duration  time.Duration
+	Timestamp time.Time
+}
+
+//ExecutionHistory records the attempts of a request.
+type ExecutionHistory struct {
+	attempts []*Attempt
+}
+
+//NewExecutionHistory ...
+func NewExecutionHistory() *ExecutionHistory {
+	return &ExecutionHistory{
+		attempts: make([]*Attempt, 0),
+	}
+}
+
+//AddAttempt ...
+func (h *ExecutionHistory) AddAttempt(attempt *Attempt) {
+	h.attempts = append(h.attempts, attempt)
+}
+
+//GetAttempts ...
+func (h *ExecutionHistory) GetAttempts() []*Attempt {
+	return h.attempts
+}
+
+//GetAttemptCount ...
+func (h *ExecutionHistory) GetAttemptCount() int {
+	return len(h.attempts)
+}
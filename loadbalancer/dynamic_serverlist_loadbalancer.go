@@ -1,9 +1,12 @@
 package loadbalancer
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
 	"github.com/nienie/marathon/server"
-	"sync/atomic"
 )
 
 //DynamicServerListLoadBalancer ...
@@ -14,50 +17,156 @@ type DynamicServerListLoadBalancer struct {
 	ServerListUpdater server.ListUpdater
 
 	serverListUpdaterInProgress int32
+	serverListSource            atomic.Value
+	serverListStale             int32
+
+	snapshotStore      *server.ListSnapshotStore
+	snapshotMaxAge     time.Duration
+	snapshotLoaded     bool
+	discoverySucceeded int32
 }
 
 //NewDynamicServerListLoadBalancer ...
 func NewDynamicServerListLoadBalancer(clientConfig config.ClientConfig, rule Rule, serverListImp server.List) *DynamicServerListLoadBalancer {
+	updater := server.NewPollingServerListUpdater(
+		clientConfig.GetPropertyAsDuration(config.ListOfServersPollingInterval, config.DefaultListOfServersPollingInterval),
+	)
+	return NewDynamicServerListLoadBalancerWithUpdater(clientConfig, rule, serverListImp, updater)
+}
+
+//NewDynamicServerListLoadBalancerWithUpdater creates a DynamicServerListLoadBalancer whose server list
+//is refreshed by the given updater, e.g. a server.EventBasedServerListUpdater notified by the discovery source.
+func NewDynamicServerListLoadBalancerWithUpdater(clientConfig config.ClientConfig, rule Rule, serverListImp server.List,
+	updater server.ListUpdater) *DynamicServerListLoadBalancer {
 	lb := &DynamicServerListLoadBalancer{
-		BaseLoadBalancer: NewBaseLoadBalancer(clientConfig, rule, nil, nil),
-		ServerListImp:    serverListImp,
-		ServerListUpdater: server.NewPollingServerListUpdater(
-			clientConfig.GetPropertyAsDuration(config.ListOfServersPollingInterval, config.DefaultListOfServersPollingInterval),
-		),
-		Filter: nil,
+		BaseLoadBalancer:            NewBaseLoadBalancer(clientConfig, rule, nil, nil),
+		ServerListImp:               serverListImp,
+		ServerListUpdater:           updater,
+		Filter:                      nil,
 		serverListUpdaterInProgress: int32(0),
+		snapshotMaxAge:              clientConfig.GetPropertyAsDuration(config.ServerListSnapshotMaxAge, config.DefaultServerListSnapshotMaxAge),
+	}
+	filter, err := server.NewServerListFilterFromConfig(clientConfig)
+	if err != nil {
+		//fail closed, the load balancer has no server until the filters are fixed
+		logger.Errorf(nil, "err_msg=invalid server list filters, all servers are filtered out||client=%s||err=%v",
+			clientConfig.GetClientName(), err)
+		filter = &server.RejectAllListFilter{}
+	}
+	if subsetFilter := server.NewSubsetListFilterFromConfig(clientConfig); subsetFilter != nil {
+		filter = server.NewChainListFilter(filter, subsetFilter)
+	}
+	lb.Filter = filter
+	snapshotFile := clientConfig.GetPropertyAsString(config.ServerListSnapshotFile, config.DefaultServerListSnapshotFile)
+	if len(snapshotFile) > 0 {
+		lb.snapshotStore = server.NewListSnapshotStore(snapshotFile)
+		lb.AddServerStatusChangeListener(&serverListSnapshotListener{lb: lb})
 	}
 	lb.init()
 	return lb
 }
 
 func (o *DynamicServerListLoadBalancer) init() {
-	o.ServerListUpdater.Start(o)
+	o.loadServerListSnapshot()
+	if o.ServerListUpdater != nil {
+		o.ServerListUpdater.Start(o)
+	}
 	o.UpdateListOfServers()
 }
 
+//loadServerListSnapshot uses the last known server list before the first discovery call succeeds.
+func (o *DynamicServerListLoadBalancer) loadServerListSnapshot() {
+	if o.snapshotStore == nil {
+		return
+	}
+	servers, err := o.snapshotStore.Load(o.snapshotMaxAge)
+	if err != nil {
+		logger.Warnf(nil, "err_msg=load server list snapshot failed||client=%s||err=%v", o.GetName(), err)
+		return
+	}
+	if len(servers) == 0 {
+		return
+	}
+	o.snapshotLoaded = true
+	o.serverListSource.Store(o.snapshotStore.GetPath())
+	atomic.StoreInt32(&o.serverListStale, 1)
+	o.SetServerList(servers)
+	logger.Infof(nil, "msg=server list snapshot loaded||client=%s||count=%d", o.GetName(), len(servers))
+}
+
+func (o *DynamicServerListLoadBalancer) saveServerListSnapshot() {
+	if o.snapshotStore == nil || atomic.LoadInt32(&o.discoverySucceeded) == 0 {
+		return
+	}
+	if err := o.snapshotStore.Save(o.GetAllServers()); err != nil {
+		logger.Warnf(nil, "err_msg=save server list snapshot failed||client=%s||err=%v", o.GetName(), err)
+	}
+}
+
 //UpdateListOfServers ...
 func (o *DynamicServerListLoadBalancer) UpdateListOfServers() {
 	if o.ServerListImp != nil {
 		servers := o.ServerListImp.GetUpdatedListOfServers()
+		o.recordServerListSource()
 
 		if o.Filter != nil {
 			servers = o.Filter.GetFilteredListOfServers(servers)
 		}
+		if len(servers) == 0 && o.snapshotLoaded && atomic.LoadInt32(&o.discoverySucceeded) == 0 {
+			//keep the servers of the snapshot until the discovery works
+			return
+		}
+		if len(servers) > 0 {
+			atomic.StoreInt32(&o.discoverySucceeded, 1)
+		}
 		o.UpdateAllServerList(servers)
 	}
 }
 
+func (o *DynamicServerListLoadBalancer) recordServerListSource() {
+	reporter, ok := o.ServerListImp.(server.ListSourceReporter)
+	if !ok {
+		return
+	}
+	o.serverListSource.Store(reporter.GetCurrentSource())
+	if reporter.IsStale() {
+		atomic.StoreInt32(&o.serverListStale, 1)
+	} else {
+		atomic.StoreInt32(&o.serverListStale, 0)
+	}
+}
+
+//GetServerListSource returns the name of the source which supplied the current server list,
+//empty if the ServerListImp does not implement server.ListSourceReporter.
+func (o *DynamicServerListLoadBalancer) GetServerListSource() string {
+	source, _ := o.serverListSource.Load().(string)
+	return source
+}
+
+//IsServerListStale returns true if the current server list is the last known one kept by the ServerListImp.
+func (o *DynamicServerListLoadBalancer) IsServerListStale() bool {
+	return atomic.LoadInt32(&o.serverListStale) == 1
+}
+
 //UpdateAllServerList ...
 func (o *DynamicServerListLoadBalancer) UpdateAllServerList(servers []*server.Server) {
 	if atomic.CompareAndSwapInt32(&o.serverListUpdaterInProgress, 0, 1) {
 		defer atomic.StoreInt32(&o.serverListUpdaterInProgress, 0)
+		knownServers := make(map[string]*server.Server)
+		for _, svr := range o.GetAllServers() {
+			knownServers[getServerKey(svr)] = svr
+		}
 		for _, svr := range servers {
+			if known, ok := knownServers[getServerKey(svr)]; ok {
+				svr.InheritState(known)
+				continue
+			}
 			svr.SetAlive(true)
 			svr.SetTempDown(false)
 		}
 		o.SetServerList(servers)
 		o.runPingTask()
+		o.saveServerListSnapshot()
 	}
 }
 
@@ -97,3 +206,13 @@ func (o *DynamicServerListLoadBalancer) Shutdown() {
 	o.BaseLoadBalancer.Shutdown()
 	o.StopServerListRefreshing()
 }
+
+//serverListSnapshotListener persists the server list when the health state of the servers changes.
+type serverListSnapshotListener struct {
+	lb *DynamicServerListLoadBalancer
+}
+
+//ServerStatusChanged ...
+func (l *serverListSnapshotListener) ServerStatusChanged(servers []*server.Server) {
+	l.lb.saveServerListSnapshot()
+}
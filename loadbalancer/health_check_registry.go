@@ -0,0 +1,262 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/loadbalancer/ping"
+	"github.com/nienie/marathon/server"
+	"github.com/nienie/marathon/utils/timer"
+)
+
+var (
+	defaultHealthCheckRegistry     *HealthCheckRegistry
+	defaultHealthCheckRegistryOnce sync.Once
+)
+
+//GetDefaultHealthCheckRegistry returns the process-wide registry used by the load balancers with PingShared enabled.
+//Every load balancer checks its servers with its own ping strategy, the default strategy is for those without one.
+func GetDefaultHealthCheckRegistry() *HealthCheckRegistry {
+	defaultHealthCheckRegistryOnce.Do(func() {
+		defaultHealthCheckRegistry = NewHealthCheckRegistry(
+			ping.NewBoundedParallelStrategy(config.DefaultPingWorkers, config.DefaultPingTimeout))
+	})
+	return defaultHealthCheckRegistry
+}
+
+//healthCheckSubscriber receives the results of the shared health checks and returns when it wants the next check.
+type healthCheckSubscriber interface {
+	onHealthCheck(svr *server.Server, result *ping.Result, now time.Time) time.Time
+}
+
+//healthCheckSubscription a server of a subscriber.
+type healthCheckSubscription struct {
+	sub healthCheckSubscriber
+	svr *server.Server
+}
+
+//healthCheckProbe a distinct health check, identified by the scheme, host, port, the ping definition
+//and the strategy definition, so the clients with different PingStrategy, PingTimeout or PingWorkers do not share it.
+type healthCheckProbe struct {
+	key         string
+	definition  string
+	svr         *server.Server
+	pingAction  ping.Ping
+	strategy    ping.Strategy
+	nextTime    time.Time
+	running     bool
+	lastResult  *ping.Result
+	subscribers map[healthCheckSubscription]bool
+}
+
+//HealthCheckRegistry runs every distinct health check only once and shares the result with all the load balancers
+//which subscribe to it, it is useful when several clients point at the same backend hosts.
+//Every probe runs as often as its most demanding subscriber asks for.
+type HealthCheckRegistry struct {
+	strategy ping.Strategy
+
+	lock          *sync.Mutex
+	probes        map[string]*healthCheckProbe
+	subscriptions map[healthCheckSubscriber]map[*server.Server]string
+
+	healthCheckTimer *timer.Timer
+	inProgress       int32
+}
+
+//NewHealthCheckRegistry ...
+func NewHealthCheckRegistry(strategy ping.Strategy) *HealthCheckRegistry {
+	if strategy == nil {
+		strategy = ping.NewParallelStrategy()
+	}
+	return &HealthCheckRegistry{
+		strategy:      strategy,
+		lock:          &sync.Mutex{},
+		probes:        make(map[string]*healthCheckProbe),
+		subscriptions: make(map[healthCheckSubscriber]map[*server.Server]string),
+	}
+}
+
+//GetProbeCount returns the number of distinct health checks.
+func (r *HealthCheckRegistry) GetProbeCount() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.probes)
+}
+
+func getProbeKey(svr *server.Server, definition string) string {
+	return fmt.Sprintf("%s://%s|%s", svr.GetScheme(), svr.GetHostPort(), definition)
+}
+
+//subscribe replaces the servers the subscriber is interested in, the probes without subscribers are dropped.
+//The subscriber receives the last result of an existing probe at once, the new probes are returned to be run.
+//A nil strategy is replaced by the strategy of the registry.
+func (r *HealthCheckRegistry) subscribe(sub healthCheckSubscriber, pingAction ping.Ping, strategy ping.Strategy,
+	servers []*server.Server) []*healthCheckProbe {
+	if strategy == nil {
+		strategy = r.strategy
+	}
+	definition := ping.GetDefinition(pingAction) + "|" + ping.GetStrategyDefinition(strategy)
+	now := time.Now()
+	type lastResult struct {
+		svr    *server.Server
+		result *ping.Result
+	}
+	lastResults := make([]lastResult, 0)
+	newProbes := make([]*healthCheckProbe, 0)
+
+	r.lock.Lock()
+	oldKeys := r.subscriptions[sub]
+	newKeys := make(map[*server.Server]string, len(servers))
+	for _, svr := range servers {
+		newKeys[svr] = getProbeKey(svr, definition)
+	}
+	for svr, key := range oldKeys {
+		if newKey, ok := newKeys[svr]; !ok || newKey != key {
+			r.removeSubscriberLocked(sub, svr, key)
+		}
+	}
+	for svr, key := range newKeys {
+		if oldKey, ok := oldKeys[svr]; ok && oldKey == key {
+			continue
+		}
+		probe, ok := r.probes[key]
+		if !ok {
+			probe = &healthCheckProbe{
+				key:         key,
+				definition:  definition,
+				svr:         server.NewServer(svr.GetScheme(), svr.GetHost(), svr.GetPort()),
+				pingAction:  pingAction,
+				strategy:    strategy,
+				nextTime:    now,
+				subscribers: make(map[healthCheckSubscription]bool),
+			}
+			r.probes[key] = probe
+			newProbes = append(newProbes, probe)
+		}
+		probe.subscribers[healthCheckSubscription{sub: sub, svr: svr}] = true
+		if probe.lastResult != nil {
+			lastResults = append(lastResults, lastResult{svr: svr, result: probe.lastResult})
+		}
+	}
+	if len(newKeys) == 0 {
+		delete(r.subscriptions, sub)
+	} else {
+		r.subscriptions[sub] = newKeys
+	}
+	r.updateTimerLocked()
+	r.lock.Unlock()
+
+	for _, last := range lastResults {
+		sub.onHealthCheck(last.svr, last.result, now)
+	}
+	return newProbes
+}
+
+//unsubscribe drops all the servers of the subscriber.
+func (r *HealthCheckRegistry) unsubscribe(sub healthCheckSubscriber) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for svr, key := range r.subscriptions[sub] {
+		r.removeSubscriberLocked(sub, svr, key)
+	}
+	delete(r.subscriptions, sub)
+	r.updateTimerLocked()
+}
+
+func (r *HealthCheckRegistry) removeSubscriberLocked(sub healthCheckSubscriber, svr *server.Server, key string) {
+	probe, ok := r.probes[key]
+	if !ok {
+		return
+	}
+	delete(probe.subscribers, healthCheckSubscription{sub: sub, svr: svr})
+	if len(probe.subscribers) == 0 {
+		delete(r.probes, key)
+	}
+}
+
+//updateTimerLocked starts the health check task with the first probe and stops it with the last one.
+func (r *HealthCheckRegistry) updateTimerLocked() {
+	if len(r.probes) == 0 {
+		if r.healthCheckTimer != nil {
+			r.healthCheckTimer.Cancel()
+			r.healthCheckTimer = nil
+		}
+		return
+	}
+	if r.healthCheckTimer == nil {
+		r.healthCheckTimer = timer.NewTimer("SharedHealthCheckTask")
+		r.healthCheckTimer.Schedule(r.runHealthChecks, minPingTick, 0)
+	}
+}
+
+//runHealthChecks runs the probes which are due.
+func (r *HealthCheckRegistry) runHealthChecks() {
+	if !atomic.CompareAndSwapInt32(&r.inProgress, 0, 1) {
+		return
+	}
+
+	defer atomic.StoreInt32(&r.inProgress, 0)
+
+	now := time.Now()
+	probes := make([]*healthCheckProbe, 0)
+	r.lock.Lock()
+	for _, probe := range r.probes {
+		if !now.Before(probe.nextTime) {
+			probes = append(probes, probe)
+		}
+	}
+	r.lock.Unlock()
+	r.runProbes(probes, now)
+}
+
+//runProbes runs the probes which are not running yet, the probes with the same ping and strategy are run together.
+func (r *HealthCheckRegistry) runProbes(probes []*healthCheckProbe, now time.Time) {
+	groups := make(map[string][]*healthCheckProbe)
+	r.lock.Lock()
+	for _, probe := range probes {
+		if probe.running {
+			continue
+		}
+		probe.running = true
+		groups[probe.definition] = append(groups[probe.definition], probe)
+	}
+	r.lock.Unlock()
+
+	for _, probes := range groups {
+		servers := make([]*server.Server, len(probes))
+		for i, probe := range probes {
+			servers[i] = probe.svr
+		}
+		results := pingServers(probes[0].strategy, probes[0].pingAction, servers)
+		for i, probe := range probes {
+			r.dispatch(probe, results[i], now)
+		}
+	}
+}
+
+//dispatch shares the result with the subscribers, the probe is run next at the earliest time they ask for.
+func (r *HealthCheckRegistry) dispatch(probe *healthCheckProbe, result *ping.Result, now time.Time) {
+	r.lock.Lock()
+	probe.lastResult = result
+	subscriptions := make([]healthCheckSubscription, 0, len(probe.subscribers))
+	for subscription := range probe.subscribers {
+		subscriptions = append(subscriptions, subscription)
+	}
+	r.lock.Unlock()
+
+	var nextTime time.Time
+	for _, subscription := range subscriptions {
+		next := subscription.sub.onHealthCheck(subscription.svr, result, now)
+		if nextTime.IsZero() || next.Before(nextTime) {
+			nextTime = next
+		}
+	}
+
+	r.lock.Lock()
+	probe.nextTime = nextTime
+	probe.running = false
+	r.lock.Unlock()
+}
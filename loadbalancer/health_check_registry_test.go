@@ -0,0 +1,114 @@
+package loadbalancer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/loadbalancer/ping"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingPing struct {
+	count int32
+}
+
+func (p *countingPing) IsAlive(svr *server.Server) bool {
+	atomic.AddInt32(&p.count, 1)
+	return true
+}
+
+type countingStrategy struct {
+	ping.Strategy
+	count int32
+}
+
+func (s *countingStrategy) PingServers(pingAction ping.Ping, servers []*server.Server) []bool {
+	atomic.AddInt32(&s.count, 1)
+	return s.Strategy.PingServers(pingAction, servers)
+}
+
+//TestHealthCheckRegistry ...
+func TestHealthCheckRegistry(t *testing.T) {
+	registry := NewHealthCheckRegistry(ping.NewSerialStrategy())
+	pingAction := &countingPing{}
+
+	lb1 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api1", nil), nil, pingAction, nil)
+	lb1.SetHealthCheckRegistry(registry)
+	svr1 := server.NewServer("http", "127.0.0.1", 8080)
+	svr1.SetAlive(false)
+	lb1.AddServers([]*server.Server{svr1})
+
+	lb2 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api2", nil), nil, pingAction, nil)
+	lb2.SetHealthCheckRegistry(registry)
+	svr2 := server.NewServer("http", "127.0.0.1", 8080)
+	svr2.SetAlive(false)
+	lb2.AddServers([]*server.Server{svr2})
+
+	assert.Equal(t, 1, registry.GetProbeCount())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pingAction.count))
+	assert.Equal(t, 1, len(lb1.GetReachableServers()))
+	assert.Equal(t, 1, len(lb2.GetReachableServers()))
+	assert.Equal(t, 1, len(lb2.GetServerHealthCheckHistory(svr2)))
+
+	lb1.Shutdown()
+	assert.Equal(t, 1, registry.GetProbeCount())
+	lb2.Shutdown()
+	assert.Equal(t, 0, registry.GetProbeCount())
+}
+
+//TestHealthCheckRegistryStrategy ...
+func TestHealthCheckRegistryStrategy(t *testing.T) {
+	registry := NewHealthCheckRegistry(ping.NewSerialStrategy())
+	pingAction := &countingPing{}
+
+	strategy := &countingStrategy{Strategy: ping.NewSerialStrategy()}
+	lb1 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api1", nil), nil, pingAction, strategy)
+	defer lb1.Shutdown()
+	lb1.SetHealthCheckRegistry(registry)
+	count := atomic.LoadInt32(&strategy.count)
+	lb1.AddServers([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	assert.Equal(t, count+1, atomic.LoadInt32(&strategy.count))
+
+	lb2 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api2", nil), nil, pingAction,
+		ping.NewBoundedParallelStrategy(2, 100*time.Millisecond))
+	defer lb2.Shutdown()
+	lb2.SetHealthCheckRegistry(registry)
+	lb2.AddServers([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	assert.Equal(t, 2, registry.GetProbeCount())
+
+	lb3 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api3", nil), nil, pingAction,
+		ping.NewBoundedParallelStrategy(2, 100*time.Millisecond))
+	defer lb3.Shutdown()
+	lb3.SetHealthCheckRegistry(registry)
+	lb3.AddServers([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	assert.Equal(t, 2, registry.GetProbeCount())
+	assert.Equal(t, count+1, atomic.LoadInt32(&strategy.count))
+}
+
+//TestHealthCheckRegistryRunsNewProbesOnly ...
+func TestHealthCheckRegistryRunsNewProbesOnly(t *testing.T) {
+	registry := NewHealthCheckRegistry(ping.NewSerialStrategy())
+	ping1, ping2 := &countingPing{}, &countingPing{}
+
+	lb1 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api1", nil), nil, ping1, nil)
+	defer lb1.Shutdown()
+	lb1.SetHealthCheckRegistry(registry)
+	lb1.AddServers([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ping1.count))
+
+	//the probe of lb1 is due, but the server list update of lb2 only runs the probe of its new server
+	registry.lock.Lock()
+	for _, probe := range registry.probes {
+		probe.nextTime = time.Now()
+	}
+	registry.lock.Unlock()
+	lb2 := NewBaseLoadBalancer(config.NewDefaultClientConfig("api2", nil), nil, ping2, nil)
+	defer lb2.Shutdown()
+	lb2.SetHealthCheckRegistry(registry)
+	lb2.AddServers([]*server.Server{server.NewServer("http", "127.0.0.2", 8080)})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ping2.count))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ping1.count))
+}
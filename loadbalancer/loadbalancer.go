@@ -1,6 +1,8 @@
 package loadbalancer
 
 import (
+	"context"
+
 	"github.com/nienie/marathon/server"
 	"github.com/nienie/marathon/loadbalancer/ping"
 )
@@ -57,4 +59,24 @@ type LoadBalancer interface {
 
 	//GetRule ...
 	GetRule() Rule
+
+	//AddServerListChangeListener ...
+	AddServerListChangeListener(listener server.ListChangeListener)
+
+	//AddServerStatusChangeListener ...
+	AddServerStatusChangeListener(listener server.StatusChangeListener)
+
+	//AddEventListener registers a listener of the state changes of the servers, the returned id removes it.
+	AddEventListener(listener EventListener) EventListenerID
+
+	//RemoveEventListener ...
+	RemoveEventListener(id EventListenerID)
+}
+
+//ContextLoadBalancer a LoadBalancer whose rule may stop choosing at the cancellation or deadline of the context.
+type ContextLoadBalancer interface {
+	LoadBalancer
+
+	//ChooseServerWithContext ...
+	ChooseServerWithContext(ctx context.Context, key interface{}) *server.Server
 }
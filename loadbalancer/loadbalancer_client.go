@@ -3,6 +3,7 @@ package loadbalancer
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/nienie/marathon/client"
 	"github.com/nienie/marathon/config"
@@ -22,34 +23,87 @@ type BaseLoadBalancerClient struct {
 
 //ExecuteWithLoadBalancer ...
 func (c *BaseLoadBalancerClient) ExecuteWithLoadBalancer(ctx context.Context, request client.Request, requestConfig config.ClientConfig) (client.Response, error) {
+	return c.ExecuteWithHistory(ctx, request, requestConfig, nil)
+}
+
+//ExecuteWithHistory records the attempts on the servers into the history if it is not nil.
+func (c *BaseLoadBalancerClient) ExecuteWithHistory(ctx context.Context, request client.Request, requestConfig config.ClientConfig,
+	history *command.ExecutionHistory) (client.Response, error) {
 	if request == nil {
 		return nil, errors.NewClientError(errors.General, fmt.Errorf("invalid parameters, request is nil"))
 	}
-	loadBalancerCommand := c.buildLoadBalancerCommand(request, requestConfig)
+	//wait for the rate limits before the server is chosen
+	reservation, err := ratelimit.Wait(ctx, request, requestConfig)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancerCommand := c.buildLoadBalancerCommand(request, requestConfig, history)
+	sent := int32(0)
 	serverOperation := command.ServerOperation(func(server *server.Server) (client.Response, error) {
 		serverStats := c.GetServerStats(server)
-		if ratelimit.Allow(request.GetURI(), serverStats, requestConfig) == false {
-			return nil, errors.NewClientError(errors.ClientThrottled, nil)
+		if err := reservation.Check(request, serverStats, requestConfig); err != nil {
+			return nil, err
 		}
+		atomic.StoreInt32(&sent, 1)
 		finalURI := c.ReconstructURIWithServer(server, request.GetURI())
 		request.ReplaceURI(finalURI)
 		watch := metric.NewBasicStopWatch()
 		watch.Start()
 		response, err := c.Client.Execute(ctx, request, requestConfig)
 		watch.Stop()
+		ratelimit.OnComplete(request.GetURI(), serverStats, requestConfig, response, watch.GetDuration(), err)
 		metric.RPC(ctx, request, response, err, watch.GetDuration())
 		return response, err
 	})
-	return loadBalancerCommand.Execute(ctx, serverOperation)
+	var response client.Response
+	run := func() error {
+		var err error
+		response, err = loadBalancerCommand.Execute(ctx, serverOperation)
+		return err
+	}
+	if c.CircuitBreakers != nil {
+		execute := run
+		run = func() error {
+			return c.CircuitBreakers.Execute(c.getCircuitBreakerEndpoint(requestConfig), execute)
+		}
+	}
+	if c.Bulkheads != nil {
+		execute := run
+		run = func() error {
+			return c.Bulkheads.Execute(ctx, c.getBulkheadGroup(requestConfig), execute)
+		}
+	}
+	err = run()
+	if err != nil && atomic.LoadInt32(&sent) == 0 {
+		//rejected by the circuit breaker, the bulkhead or the rate limits of every attempt, or no server is available
+		reservation.Cancel()
+	}
+	return response, err
+}
+
+func (c *BaseLoadBalancerClient) getBulkheadGroup(requestConfig config.ClientConfig) string {
+	if requestConfig == nil {
+		return ""
+	}
+	return requestConfig.GetPropertyAsString(config.BulkheadGroup, config.DefaultBulkheadGroup)
+}
+
+func (c *BaseLoadBalancerClient) getCircuitBreakerEndpoint(requestConfig config.ClientConfig) string {
+	if requestConfig == nil {
+		return ""
+	}
+	return requestConfig.GetPropertyAsString(config.CircuitBreakerEndpoint, config.DefaultCircuitBreakerEndpoint)
 }
 
-func (c *BaseLoadBalancerClient) buildLoadBalancerCommand(request client.Request, requestConfig config.ClientConfig) *Command {
+func (c *BaseLoadBalancerClient) buildLoadBalancerCommand(request client.Request, requestConfig config.ClientConfig,
+	history *command.ExecutionHistory) *Command {
 	cmd := NewCommand()
 	cmd.WithLoadBalancer(c.LoadBalancer)
 	cmd.WithLoadBalancerContext(c.Context)
 	cmd.WithServerLocator(request.GetLoadBalancerKey())
 	cmd.WithLoadBalancerURI(request.GetURI())
 	cmd.WithRetryHandler(c.getRequestSpecificRetryHandler(request, requestConfig))
+	cmd.WithExecutionHistory(history)
 	return cmd
 }
 
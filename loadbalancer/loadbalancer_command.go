@@ -21,6 +21,7 @@ type Command struct {
 	LoadBalancer        LoadBalancer
 	RetryHandler        retry.Handler
 	Server              *server.Server
+	History             *command.ExecutionHistory
 }
 
 //NewCommand ...
@@ -64,12 +65,18 @@ func (c *Command) WithServer(server *server.Server) *Command {
 	return c
 }
 
+//WithExecutionHistory records the attempts of the command into the history.
+func (c *Command) WithExecutionHistory(history *command.ExecutionHistory) *Command {
+	c.History = history
+	return c
+}
+
 //SelectServer ...
-func (c *Command) SelectServer() (*server.Server, error) {
+func (c *Command) SelectServer(ctx context.Context) (*server.Server, error) {
 	if c.Server != nil {
 		return c.Server, nil
 	}
-	return c.LoadBalancerContext.GetServerFromLoadBalancer(c.LoadBalancerURI, c.LoadBalancerKey)
+	return c.LoadBalancerContext.GetServerFromLoadBalancerWithContext(ctx, c.LoadBalancerURI, c.LoadBalancerKey)
 }
 
 //Execute ...
@@ -78,7 +85,7 @@ func (c *Command) Execute(ctx context.Context, serverOperation command.ServerOpe
 	maxRetrySame := c.RetryHandler.GetMaxRetriesOnSameServer()
 	maxRetryNext := c.RetryHandler.GetMaxRetriesOnNextServer()
 
-	server, err := c.SelectServer()
+	server, err := c.SelectServer(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +118,7 @@ func (c *Command) Execute(ctx context.Context, serverOperation command.ServerOpe
 	//retry on different serverss
 	if maxRetryNext > 0 && c.Server == nil {
 		for {
-			server, err = c.SelectServer()
+			server, err = c.SelectServer(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -144,6 +151,14 @@ func (c *Command) execute(ctx context.Context, exeCtx *command.ExecutionInfoCont
 	stopWatch.Start()
 	response, err := operation(server)
 	stopWatch.Stop()
+	if c.History != nil {
+		c.History.AddAttempt(&command.Attempt{
+			Server:    server,
+			Err:       err,
+			Duration:  stopWatch.GetDuration(),
+			Timestamp: time.Now(),
+		})
+	}
 	c.recordStats(ctx, stats, response, err, stopWatch.GetDuration())
 	return response, err
 }
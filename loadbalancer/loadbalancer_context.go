@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nienie/marathon/bulkhead"
+	"github.com/nienie/marathon/circuitbreaker"
 	"github.com/nienie/marathon/client"
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/errors"
@@ -18,17 +20,23 @@ import (
 
 //Context A class contains APIs intended to be used be load balancing client which is subclass of this class.
 type Context struct {
-	ClientName   string
-	LoadBalancer LoadBalancer
-	RetryHandler retry.Handler
+	ClientName      string
+	LoadBalancer    LoadBalancer
+	RetryHandler    retry.Handler
+	WaitQueue       *ServerWaitQueue
+	CircuitBreakers *circuitbreaker.Registry //nil if the circuit breaker is off
+	Bulkheads       *bulkhead.Registry       //nil if the bulkhead is off
 }
 
 //NewLoadBalancerContext ...
 func NewLoadBalancerContext(clientConfig config.ClientConfig, lb LoadBalancer) *Context {
 	ctx := &Context{
-		ClientName:   clientConfig.GetClientName(),
-		LoadBalancer: lb,
-		RetryHandler: retry.NewLoadBalancerRetryHandler(clientConfig),
+		ClientName:      clientConfig.GetClientName(),
+		LoadBalancer:    lb,
+		RetryHandler:    retry.NewLoadBalancerRetryHandler(clientConfig),
+		WaitQueue:       NewServerWaitQueueFromConfig(clientConfig),
+		CircuitBreakers: circuitbreaker.NewRegistryFromConfig(clientConfig),
+		Bulkheads:       bulkhead.NewRegistryFromConfig(clientConfig),
 	}
 
 	return ctx
@@ -46,6 +54,18 @@ func (o *Context)SetRetryHandler(handler retry.Handler) *Context {
 	return o
 }
 
+//SetCircuitBreakers ...
+func (o *Context) SetCircuitBreakers(registry *circuitbreaker.Registry) *Context {
+	o.CircuitBreakers = registry
+	return o
+}
+
+//SetBulkheads ...
+func (o *Context) SetBulkheads(registry *bulkhead.Registry) *Context {
+	o.Bulkheads = registry
+	return o
+}
+
 //SetClientName ...
 func (o *Context)SetClientName(name string) *Context {
 	o.ClientName = name
@@ -166,6 +186,13 @@ func (o *Context) deriveSchemeAndPortFromPartialURI(uri *url.URL) (port int, sch
 
 //GetServerFromLoadBalancer compute the final URI from a partial URI in the request.
 func (o *Context) GetServerFromLoadBalancer(original *url.URL, loadBalancerKey interface{}) (*server.Server, error) {
+	return o.GetServerFromLoadBalancerWithContext(context.Background(), original, loadBalancerKey)
+}
+
+//GetServerFromLoadBalancerWithContext if the load balancer has no available server, the request waits in the
+//WaitQueue within the deadline of the context.
+func (o *Context) GetServerFromLoadBalancerWithContext(ctx context.Context, original *url.URL,
+	loadBalancerKey interface{}) (*server.Server, error) {
 	var (
 		scheme string
 		host   string
@@ -184,9 +211,22 @@ func (o *Context) GetServerFromLoadBalancer(original *url.URL, loadBalancerKey i
 		//Partial URI or no URI case
 		//well we have to just get the right instance from lb - or we fall back
 		if lb != nil {
-			svc := lb.ChooseServer(loadBalancerKey)
+			if o.WaitQueue != nil {
+				//the request waits in the WaitQueue only, not in a RetryRule as well
+				ctx = withServerWaitQueue(ctx)
+			}
+			svc := chooseServer(ctx, lb, loadBalancerKey)
+			if svc == nil && o.WaitQueue != nil {
+				var err error
+				svc, err = o.WaitQueue.Wait(ctx, lb, func() *server.Server {
+					return chooseServer(ctx, lb, loadBalancerKey)
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
 			if svc == nil {
-				return nil, errors.NewClientError(errors.General, fmt.Errorf("Load balancer does not have available server for client: %s", o.ClientName))
+				return nil, errors.NewClientError(errors.NoAvailableServer, fmt.Errorf("Load balancer does not have available server for client: %s", o.ClientName))
 			}
 			host = svc.GetHost()
 			if len(host) == 0 {
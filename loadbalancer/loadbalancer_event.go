@@ -0,0 +1,132 @@
+package loadbalancer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/server"
+)
+
+//EventType the type of the state change of a server in a load balancer
+type EventType int
+
+const (
+	//EventServerAdded the server is added to the server list.
+	EventServerAdded EventType = iota
+	//EventServerRemoved the server is removed from the server list.
+	EventServerRemoved
+	//EventServerUp the server passes the health check.
+	EventServerUp
+	//EventServerDown the server fails the health check or is marked down.
+	EventServerDown
+	//EventCircuitOpened the circuit breaker of the server is tripped.
+	EventCircuitOpened
+	//EventCircuitClosed the circuit breaker of the server is closed again.
+	EventCircuitClosed
+	//EventServerEjected the server is taken out of rotation temporarily.
+	EventServerEjected
+	//EventServerRestored the ejected server is put back into rotation.
+	EventServerRestored
+	//EventServerDraining the server stops receiving new requests.
+	EventServerDraining
+)
+
+var eventTypeNames = map[EventType]string{
+	EventServerAdded:    "ServerAdded",
+	EventServerRemoved:  "ServerRemoved",
+	EventServerUp:       "ServerUp",
+	EventServerDown:     "ServerDown",
+	EventCircuitOpened:  "CircuitOpened",
+	EventCircuitClosed:  "CircuitClosed",
+	EventServerEjected:  "ServerEjected",
+	EventServerRestored: "ServerRestored",
+	EventServerDraining: "ServerDraining",
+}
+
+//String ...
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+//Event a state change of a server in a load balancer
+type Event struct {
+	Type         EventType
+	LoadBalancer string
+	Server       *server.Server
+	Reason       string
+	Timestamp    time.Time
+}
+
+//NewEvent ...
+func NewEvent(eventType EventType, loadBalancer string, svr *server.Server, reason string) *Event {
+	return &Event{
+		Type:         eventType,
+		LoadBalancer: loadBalancer,
+		Server:       svr,
+		Reason:       reason,
+		Timestamp:    time.Now(),
+	}
+}
+
+//EventListener receives the events of a load balancer, it is called synchronously so it should not block.
+type EventListener interface {
+	//OnEvent ...
+	OnEvent(event *Event)
+}
+
+//EventListenerID identifies a registered EventListener, the listeners need not be comparable.
+type EventListenerID uint64
+
+//registeredEventListener ...
+type registeredEventListener struct {
+	id       EventListenerID
+	listener EventListener
+}
+
+//ChannelEventListener delivers the events to a buffered channel, the events are dropped when the channel is full,
+//so that a slow consumer never blocks the load balancer.
+type ChannelEventListener struct {
+	events  chan *Event
+	dropped int64
+}
+
+//NewChannelEventListener ...
+func NewChannelEventListener(size int) *ChannelEventListener {
+	if size < 0 {
+		size = 0
+	}
+	return &ChannelEventListener{
+		events: make(chan *Event, size),
+	}
+}
+
+//OnEvent ...
+func (l *ChannelEventListener) OnEvent(event *Event) {
+	select {
+	case l.events <- event:
+	default:
+		atomic.AddInt64(&l.dropped, 1)
+	}
+}
+
+//Events ...
+func (l *ChannelEventListener) Events() <-chan *Event {
+	return l.events
+}
+
+//GetDroppedCount returns the number of events dropped because the channel was full.
+func (l *ChannelEventListener) GetDroppedCount() int64 {
+	return atomic.LoadInt64(&l.dropped)
+}
+
+//SubscribeEvents registers a ChannelEventListener on the load balancer, the returned function removes it.
+func SubscribeEvents(lb LoadBalancer, size int) (<-chan *Event, func()) {
+	listener := NewChannelEventListener(size)
+	id := lb.AddEventListener(listener)
+	return listener.Events(), func() {
+		lb.RemoveEventListener(id)
+	}
+}
@@ -0,0 +1,86 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func drainEvents(events <-chan *Event) []EventType {
+	types := make([]EventType, 0)
+	for {
+		select {
+		case event := <-events:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+//TestLoadBalancerEvents ...
+func TestLoadBalancerEvents(t *testing.T) {
+	pingAction := &scriptedPing{alive: true}
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, pingAction, nil)
+	defer lb.Shutdown()
+	events, cancel := SubscribeEvents(lb, 16)
+
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	svr.SetAlive(false)
+	lb.AddServers([]*server.Server{svr})
+	assert.Equal(t, []EventType{EventServerAdded, EventServerUp}, drainEvents(events))
+
+	lb.MarkServerTempDown(svr)
+	assert.Equal(t, []EventType{EventServerEjected}, drainEvents(events))
+	lb.MarkServerReady(svr)
+	assert.Equal(t, []EventType{EventServerRestored}, drainEvents(events))
+
+	lb.MarkServerDown(svr)
+	assert.Equal(t, []EventType{EventServerDown}, drainEvents(events))
+
+	lb.SetServerList([]*server.Server{})
+	assert.Equal(t, []EventType{EventServerRemoved}, drainEvents(events))
+
+	cancel()
+	lb.AddServers([]*server.Server{svr})
+	assert.Equal(t, 0, len(drainEvents(events)))
+}
+
+//TestChannelEventListenerDrops ...
+func TestChannelEventListenerDrops(t *testing.T) {
+	listener := NewChannelEventListener(1)
+	listener.OnEvent(NewEvent(EventServerUp, "test", nil, ""))
+	listener.OnEvent(NewEvent(EventServerDown, "test", nil, ""))
+	assert.Equal(t, int64(1), listener.GetDroppedCount())
+	assert.Equal(t, EventServerUp, (<-listener.Events()).Type)
+}
+
+//sliceEventListener is not comparable, comparing it as an interface panics.
+type sliceEventListener []EventType
+
+func (l sliceEventListener) OnEvent(event *Event) {}
+
+//TestLoadBalancerEventsByServerKey ...
+func TestLoadBalancerEventsByServerKey(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, &scriptedPing{alive: true}, nil)
+	defer lb.Shutdown()
+	id := lb.AddEventListener(sliceEventListener{})
+	events, cancel := SubscribeEvents(lb, 16)
+	defer cancel()
+
+	lb.SetServerList([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	assert.Equal(t, []EventType{EventServerAdded}, drainEvents(events))
+
+	lb.SetServerList([]*server.Server{server.NewServer("http", "127.0.0.1", 8080),
+		server.NewServer("http", "127.0.0.1", 8081)})
+	assert.Equal(t, []EventType{EventServerAdded}, drainEvents(events))
+
+	lb.SetServerList([]*server.Server{server.NewServer("http", "127.0.0.1", 8081)})
+	assert.Equal(t, []EventType{EventServerRemoved}, drainEvents(events))
+
+	assert.NotPanics(t, func() {
+		lb.RemoveEventListener(id)
+	})
+}
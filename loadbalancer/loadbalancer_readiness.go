@@ -0,0 +1,125 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/errors"
+)
+
+const (
+	readinessCheckInterval = 100 * time.Millisecond
+)
+
+//IsReady whether the load balancer has at least minHealthy reachable servers.
+func IsReady(lb LoadBalancer, minHealthy int) bool {
+	if minHealthy <= 0 {
+		minHealthy = 1
+	}
+	return len(lb.GetReachableServers()) >= minHealthy
+}
+
+//WaitReady blocks until the load balancer has at least minHealthy reachable servers or the context is done.
+//It is woken up by the events of the load balancer, and checks periodically as well, e.g. for a server list
+//set without health check.
+func WaitReady(ctx context.Context, lb LoadBalancer, minHealthy int) error {
+	if lb == nil {
+		return errors.NewClientError(errors.General, fmt.Errorf("invalid parameters, load balancer is nil"))
+	}
+	if IsReady(lb, minHealthy) {
+		return nil
+	}
+
+	listener := NewChannelEventListener(1)
+	defer lb.RemoveEventListener(lb.AddEventListener(listener))
+	ticker := time.NewTicker(readinessCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-listener.Events():
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.NewClientError(errors.LoadBalancerNotReady,
+				fmt.Errorf("reachable servers=%d, expected=%d, err=%v", len(lb.GetReachableServers()), minHealthy, ctx.Err()))
+		}
+		if IsReady(lb, minHealthy) {
+			return nil
+		}
+	}
+}
+
+//WaitReady ...
+func (o *BaseLoadBalancer) WaitReady(ctx context.Context, minHealthy int) error {
+	return WaitReady(ctx, o, minHealthy)
+}
+
+//WaitReady ...
+func (c *BaseLoadBalancerClient) WaitReady(ctx context.Context, minHealthy int) error {
+	return WaitReady(ctx, c.LoadBalancer, minHealthy)
+}
+
+type readinessCheck struct {
+	name       string
+	lb         LoadBalancer
+	minHealthy int
+}
+
+//ReadinessProbe checks a group of load balancers, it can serve a /ready endpoint directly.
+type ReadinessProbe struct {
+	lock   *sync.RWMutex
+	checks []*readinessCheck
+}
+
+//NewReadinessProbe ...
+func NewReadinessProbe() *ReadinessProbe {
+	return &ReadinessProbe{
+		lock:   &sync.RWMutex{},
+		checks: make([]*readinessCheck, 0),
+	}
+}
+
+//Add requires the load balancer to have at least minHealthy reachable servers.
+func (p *ReadinessProbe) Add(name string, lb LoadBalancer, minHealthy int) *ReadinessProbe {
+	if lb == nil {
+		return p
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.checks = append(p.checks, &readinessCheck{
+		name:       name,
+		lb:         lb,
+		minHealthy: minHealthy,
+	})
+	return p
+}
+
+//Check returns an error naming the load balancers which are not ready, nil if all of them are ready.
+func (p *ReadinessProbe) Check() error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	notReady := make([]string, 0)
+	for _, check := range p.checks {
+		if !IsReady(check.lb, check.minHealthy) {
+			notReady = append(notReady, fmt.Sprintf("%s(%d/%d)", check.name, len(check.lb.GetReachableServers()), check.minHealthy))
+		}
+	}
+	if len(notReady) > 0 {
+		return errors.NewClientError(errors.LoadBalancerNotReady, fmt.Errorf("not ready: %s", strings.Join(notReady, ",")))
+	}
+	return nil
+}
+
+//ServeHTTP responds 200 if all the load balancers are ready, otherwise 503.
+func (p *ReadinessProbe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := p.Check(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,57 @@
+package loadbalancer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestWaitReady ...
+func TestWaitReady(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, nil, nil)
+	defer lb.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := lb.WaitReady(ctx, 1)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.LoadBalancerNotReady, err.(errors.ClientError).GetErrType())
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		lb.AddServers([]*server.Server{server.NewServer("http", "127.0.0.1", 8080)})
+	}()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	start := time.Now()
+	assert.Nil(t, lb.WaitReady(ctx2, 1))
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
+
+//TestReadinessProbe ...
+func TestReadinessProbe(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, nil, nil)
+	defer lb.Shutdown()
+	probe := NewReadinessProbe().Add("test", lb, 2)
+
+	recorder := httptest.NewRecorder()
+	probe.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "test(0/2)")
+
+	lb.AddServers([]*server.Server{
+		server.NewServer("http", "127.0.0.1", 8080),
+		server.NewServer("http", "127.0.0.1", 8081),
+	})
+	assert.Nil(t, probe.Check())
+	recorder = httptest.NewRecorder()
+	probe.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
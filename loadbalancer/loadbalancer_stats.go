@@ -18,8 +18,9 @@ type Stats struct {
 	MaxCircuitTrippedTimeout       time.Duration
 	ResponseTimeWindowSize         int
 	RequestCountsSlidingWindowSize int
+	HealthCheckHistorySize         int
 
-	serverStatsMap     map[*server.Server]*server.Stats
+	serverStatsMap     map[string]*server.Stats //keyed by scheme and host:port to survive the server list updates
 	serverStatsLock    sync.RWMutex
 	clusterStatsMap    map[string]*ClusterStats
 	clusterStatsLock   sync.RWMutex
@@ -41,11 +42,13 @@ func NewLoadBalancerStats(clientConfig config.ClientConfig) *Stats {
 			config.DefaultResponseTimeWindowSize),
 		RequestCountsSlidingWindowSize: clientConfig.GetPropertyAsInteger(config.RequestCountsSlidingWindowSize,
 			config.DefaultRequestCountsSlidingWindowSize),
+		HealthCheckHistorySize: clientConfig.GetPropertyAsInteger(config.PingHistorySize,
+			config.DefaultPingHistorySize),
 		clusterStatsMap:    make(map[string]*ClusterStats),
 		clusterStatsLock:   sync.RWMutex{},
 		upServerClusterMap: make(map[string][]*server.Server),
 		serverClusterLock:  sync.RWMutex{},
-		serverStatsMap:     make(map[*server.Server]*server.Stats),
+		serverStatsMap:     make(map[string]*server.Stats),
 		serverStatsLock:    sync.RWMutex{},
 	}
 	return loadBalancerStats
@@ -59,6 +62,7 @@ func (o *Stats) CreateServerStats(svr *server.Server) *server.Stats {
 	ss.MaxCircuitTrippedTimeout = o.MaxCircuitTrippedTimeout
 	ss.ResponseTimeWindowSize = o.ResponseTimeWindowSize
 	ss.RequestCountsSlidingWindowSize = o.RequestCountsSlidingWindowSize
+	ss.HealthCheckHistorySize = o.HealthCheckHistorySize
 	ss.Initialize(svr)
 	return ss
 }
@@ -83,10 +87,11 @@ func (o *Stats) GetSingleServerStats(svr *server.Server) *server.Stats {
 	}
 	o.serverStatsLock.Lock()
 	defer o.serverStatsLock.Unlock()
-	ss, ok := o.serverStatsMap[svr]
+	key := getServerKey(svr)
+	ss, ok := o.serverStatsMap[key]
 	if !ok {
 		ss = o.CreateServerStats(svr)
-		o.serverStatsMap[svr] = ss
+		o.serverStatsMap[key] = ss
 	}
 	return ss
 }
@@ -138,8 +143,8 @@ func (o *Stats) GetAllServerStats() map[*server.Server]*server.Stats {
 	o.serverStatsLock.RLock()
 	defer o.serverStatsLock.RUnlock()
 	serverStatsMap := make(map[*server.Server]*server.Stats)
-	for s, ss := range o.serverStatsMap {
-		serverStatsMap[s] = ss
+	for _, ss := range o.serverStatsMap {
+		serverStatsMap[ss.Server] = ss
 	}
 	return serverStatsMap
 }
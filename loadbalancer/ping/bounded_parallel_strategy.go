@@ -0,0 +1,127 @@
+package ping
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+)
+
+const (
+	//DefaultPingWorkers ...
+	DefaultPingWorkers = 10
+)
+
+//Result the result of a single ping
+type Result struct {
+	Alive    bool
+	Latency  time.Duration
+	TimedOut bool
+}
+
+//ResultStrategy is a Strategy which also reports the latency of every ping.
+type ResultStrategy interface {
+	Strategy
+
+	//PingServersWithResults ...
+	PingServersWithResults(ping Ping, servers []*server.Server) []*Result
+}
+
+//BoundedParallelStrategy pings the servers with a pool of workers, every ping has a deadline,
+//a ping which does not return before the deadline is considered failed, so a hung ping never blocks the cycle.
+//Note that the goroutine of a hung ping exits only when the Ping itself returns.
+type BoundedParallelStrategy struct {
+	workers int
+	timeout time.Duration
+}
+
+//NewBoundedParallelStrategy ...
+func NewBoundedParallelStrategy(workers int, timeout time.Duration) Strategy {
+	if workers <= 0 {
+		workers = DefaultPingWorkers
+	}
+	if timeout <= 0 {
+		timeout = config.DefaultPingTimeout
+	}
+	return &BoundedParallelStrategy{
+		workers: workers,
+		timeout: timeout,
+	}
+}
+
+//NewBoundedParallelStrategyFromConfig creates a BoundedParallelStrategy with PingWorkers and PingTimeout.
+func NewBoundedParallelStrategyFromConfig(clientConfig config.ClientConfig) Strategy {
+	return NewBoundedParallelStrategy(
+		clientConfig.GetPropertyAsInteger(config.PingWorkers, config.DefaultPingWorkers),
+		clientConfig.GetPropertyAsDuration(config.PingTimeout, config.DefaultPingTimeout),
+	)
+}
+
+//GetDefinition ...
+func (o *BoundedParallelStrategy) GetDefinition() string {
+	return fmt.Sprintf("bounded_parallel|workers=%d|timeout=%s", o.workers, o.timeout)
+}
+
+//PingServers ...
+func (o *BoundedParallelStrategy) PingServers(ping Ping, servers []*server.Server) []bool {
+	results := o.PingServersWithResults(ping, servers)
+	ret := make([]bool, len(results))
+	for i, result := range results {
+		ret[i] = result.Alive
+	}
+	return ret
+}
+
+//PingServersWithResults ...
+func (o *BoundedParallelStrategy) PingServersWithResults(ping Ping, servers []*server.Server) []*Result {
+	numCandidates := len(servers)
+	results := make([]*Result, numCandidates)
+	jobs := make(chan int, numCandidates)
+	for i := 0; i < numCandidates; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	workers := o.workers
+	if workers > numCandidates {
+		workers = numCandidates
+	}
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = o.pingWithDeadline(ping, servers[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (o *BoundedParallelStrategy) pingWithDeadline(ping Ping, svr *server.Server) *Result {
+	start := time.Now()
+	done := make(chan bool, 1)
+	go func() {
+		done <- tryPing(ping, svr)
+	}()
+
+	deadline := time.NewTimer(o.timeout)
+	defer deadline.Stop()
+	select {
+	case isAlive := <-done:
+		return &Result{
+			Alive:   isAlive,
+			Latency: time.Since(start),
+		}
+	case <-deadline.C:
+		return &Result{
+			Alive:    false,
+			Latency:  o.timeout,
+			TimedOut: true,
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ping
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type hangingPing struct {
+	hangPort int
+	running  int32
+	maxRun   int32
+}
+
+func (p *hangingPing) IsAlive(svr *server.Server) bool {
+	n := atomic.AddInt32(&p.running, 1)
+	defer atomic.AddInt32(&p.running, -1)
+	for {
+		max := atomic.LoadInt32(&p.maxRun)
+		if n <= max || atomic.CompareAndSwapInt32(&p.maxRun, max, n) {
+			break
+		}
+	}
+	if svr.GetPort() == p.hangPort {
+		time.Sleep(time.Second)
+	}
+	time.Sleep(10 * time.Millisecond)
+	return true
+}
+
+//TestBoundedParallelStrategy ...
+func TestBoundedParallelStrategy(t *testing.T) {
+	servers := make([]*server.Server, 0)
+	for port := 8000; port < 8008; port++ {
+		servers = append(servers, server.NewServer("http", "127.0.0.1", port))
+	}
+	p := &hangingPing{hangPort: 8003}
+	strategy := NewBoundedParallelStrategy(2, 100*time.Millisecond).(ResultStrategy)
+
+	start := time.Now()
+	results := strategy.PingServersWithResults(p, servers)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	assert.Equal(t, len(servers), len(results))
+	for i, result := range results {
+		if servers[i].GetPort() == 8003 {
+			assert.False(t, result.Alive)
+			assert.True(t, result.TimedOut)
+		} else {
+			assert.True(t, result.Alive)
+			assert.False(t, result.TimedOut)
+			assert.True(t, result.Latency >= 10*time.Millisecond)
+		}
+	}
+	assert.True(t, atomic.LoadInt32(&p.maxRun) <= 3)
+}
@@ -1,6 +1,8 @@
 package ping
 
 import (
+	"fmt"
+
 	"github.com/nienie/marathon/server"
 )
 
@@ -10,6 +12,21 @@ type Ping interface {
 	IsAlive(*server.Server) bool
 }
 
+//Definition is implemented by the pings which can be shared, two pings with the same definition
+//check a server in the same way.
+type Definition interface {
+	//GetDefinition ...
+	GetDefinition() string
+}
+
+//GetDefinition returns the definition of the ping, a ping which does not implement Definition is only equal to itself.
+func GetDefinition(ping Ping) string {
+	if d, ok := ping.(Definition); ok {
+		return d.GetDefinition()
+	}
+	return fmt.Sprintf("%T@%p", ping, ping)
+}
+
 //NoOpPing ...
 type NoOpPing struct{}
 
@@ -17,3 +34,8 @@ type NoOpPing struct{}
 func (o *NoOpPing) IsAlive(server *server.Server) bool {
 	return true
 }
+
+//GetDefinition ...
+func (o *NoOpPing) GetDefinition() string {
+	return "noop"
+}
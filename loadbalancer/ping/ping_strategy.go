@@ -1,6 +1,7 @@
 package ping
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/nienie/marathon/logger"
@@ -15,6 +16,15 @@ type Strategy interface {
 	PingServers(ping Ping, servers []*server.Server) []bool
 }
 
+//GetStrategyDefinition returns the definition of the strategy, a strategy which does not implement Definition
+//is only equal to itself.
+func GetStrategyDefinition(strategy Strategy) string {
+	if d, ok := strategy.(Definition); ok {
+		return d.GetDefinition()
+	}
+	return fmt.Sprintf("%T@%p", strategy, strategy)
+}
+
 //SerialStrategy performs ping serially
 type SerialStrategy struct{}
 
@@ -34,6 +44,11 @@ func (o *SerialStrategy) PingServers(ping Ping, servers []*server.Server) []bool
 	return results
 }
 
+//GetDefinition ...
+func (o *SerialStrategy) GetDefinition() string {
+	return "serial"
+}
+
 //ParallelStrategy performs ping concurrently.
 type ParallelStrategy struct{}
 
@@ -58,6 +73,11 @@ func (o *ParallelStrategy) PingServers(ping Ping, servers []*server.Server) []bo
 	return results
 }
 
+//GetDefinition ...
+func (o *ParallelStrategy) GetDefinition() string {
+	return "parallel"
+}
+
 func tryPing(ping Ping, server *server.Server) bool {
 	isAlive := false
 	defer func() {
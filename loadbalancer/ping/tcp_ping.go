@@ -0,0 +1,153 @@
+package ping
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/server"
+)
+
+const (
+	maxExpectResponseSize = 4096
+)
+
+//TCPPing checks a server by opening a TCP connection to it within the timeout.
+type TCPPing struct {
+	Timeout time.Duration
+}
+
+//NewTCPPing ...
+func NewTCPPing(timeout time.Duration) Ping {
+	if timeout <= 0 {
+		timeout = config.DefaultPingTimeout
+	}
+	return &TCPPing{
+		Timeout: timeout,
+	}
+}
+
+//IsAlive ...
+func (p *TCPPing) IsAlive(svr *server.Server) bool {
+	conn, err := net.DialTimeout("tcp", svr.GetHostPort(), p.Timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+//TCPSendExpectPing checks a server speaking a custom protocol, it writes the payload and matches the reply
+//against a byte prefix or a regexp, e.g. Redis "PING\r\n" expects the prefix "+PONG".
+type TCPSendExpectPing struct {
+	Timeout      time.Duration
+	Send         []byte
+	ExpectPrefix []byte
+	ExpectRegexp *regexp.Regexp
+}
+
+//NewTCPSendExpectPing ...
+func NewTCPSendExpectPing(timeout time.Duration, send, expectPrefix []byte, expectRegexp *regexp.Regexp) Ping {
+	if timeout <= 0 {
+		timeout = config.DefaultPingTimeout
+	}
+	return &TCPSendExpectPing{
+		Timeout:      timeout,
+		Send:         send,
+		ExpectPrefix: expectPrefix,
+		ExpectRegexp: expectRegexp,
+	}
+}
+
+//NewTCPPingFromConfig creates a TCPSendExpectPing if any of PingSend, PingExpectPrefix and PingExpectRegex is
+//configured, otherwise a TCPPing. Escape sequences like \r\n are allowed in PingSend and PingExpectPrefix.
+func NewTCPPingFromConfig(clientConfig config.ClientConfig) Ping {
+	timeout := clientConfig.GetPropertyAsDuration(config.PingTimeout, config.DefaultPingTimeout)
+	send := unescapePingPayload(clientConfig.GetPropertyAsString(config.PingSend, config.DefaultPingSend))
+	expectPrefix := unescapePingPayload(clientConfig.GetPropertyAsString(config.PingExpectPrefix, config.DefaultPingExpectPrefix))
+	expectRegex := clientConfig.GetPropertyAsString(config.PingExpectRegex, config.DefaultPingExpectRegex)
+	if len(send) == 0 && len(expectPrefix) == 0 && len(expectRegex) == 0 {
+		return NewTCPPing(timeout)
+	}
+
+	var re *regexp.Regexp
+	if len(expectRegex) > 0 {
+		var err error
+		re, err = regexp.Compile(expectRegex)
+		if err != nil {
+			logger.Warnf(nil, "err_msg=invalid PingExpectRegex %s||client=%s||err=%v", expectRegex, clientConfig.GetClientName(), err)
+			return NewTCPPing(timeout)
+		}
+	}
+	return NewTCPSendExpectPing(timeout, send, expectPrefix, re)
+}
+
+func unescapePingPayload(payload string) []byte {
+	if len(payload) == 0 {
+		return nil
+	}
+	s, err := strconv.Unquote(`"` + payload + `"`)
+	if err != nil {
+		return []byte(payload)
+	}
+	return []byte(s)
+}
+
+//IsAlive ...
+func (p *TCPSendExpectPing) IsAlive(svr *server.Server) bool {
+	conn, err := net.DialTimeout("tcp", svr.GetHostPort(), p.Timeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(p.Timeout))
+
+	if len(p.Send) > 0 {
+		if _, err = conn.Write(p.Send); err != nil {
+			return false
+		}
+	}
+	if len(p.ExpectPrefix) == 0 && p.ExpectRegexp == nil {
+		return true
+	}
+
+	reply := make([]byte, 0, maxExpectResponseSize)
+	buf := make([]byte, maxExpectResponseSize)
+	for len(reply) < maxExpectResponseSize {
+		n, err := conn.Read(buf[:maxExpectResponseSize-len(reply)])
+		reply = append(reply, buf[:n]...)
+		if p.match(reply) {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+func (p *TCPSendExpectPing) match(reply []byte) bool {
+	if len(p.ExpectPrefix) > 0 {
+		return bytes.HasPrefix(reply, p.ExpectPrefix)
+	}
+	return p.ExpectRegexp.Match(reply)
+}
+
+//GetDefinition ...
+func (p *TCPPing) GetDefinition() string {
+	return fmt.Sprintf("tcp|%s", p.Timeout)
+}
+
+//GetDefinition ...
+func (p *TCPSendExpectPing) GetDefinition() string {
+	expectRegexp := ""
+	if p.ExpectRegexp != nil {
+		expectRegexp = p.ExpectRegexp.String()
+	}
+	return fmt.Sprintf("tcp|%s|%q|%q|%q", p.Timeout, p.Send, p.ExpectPrefix, expectRegexp)
+}
@@ -0,0 +1,50 @@
+package ping
+
+import (
+	"bufio"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func startPongServer(t *testing.T) (net.Listener, *server.Server) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, _ := bufio.NewReader(conn).ReadString('\n')
+				if line == "PING\r\n" {
+					conn.Write([]byte("+PONG\r\n"))
+				} else {
+					conn.Write([]byte("-ERR unknown command\r\n"))
+				}
+			}(conn)
+		}
+	}()
+	addr := l.Addr().(*net.TCPAddr)
+	return l, server.NewServer("tcp", "127.0.0.1", addr.Port)
+}
+
+//TestTCPPing ...
+func TestTCPPing(t *testing.T) {
+	l, svr := startPongServer(t)
+	defer l.Close()
+
+	assert.True(t, NewTCPPing(time.Second).IsAlive(svr))
+	assert.True(t, NewTCPSendExpectPing(time.Second, []byte("PING\r\n"), []byte("+PONG"), nil).IsAlive(svr))
+	assert.True(t, NewTCPSendExpectPing(time.Second, []byte("PING\r\n"), nil, regexp.MustCompile(`PONG`)).IsAlive(svr))
+	assert.False(t, NewTCPSendExpectPing(time.Second, []byte("VERSION\r\n"), []byte("+PONG"), nil).IsAlive(svr))
+
+	l.Close()
+	assert.False(t, NewTCPPing(time.Second).IsAlive(svr))
+}
@@ -1,10 +1,16 @@
 package ping
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
 	"github.com/nienie/marathon/server"
+	httputil "github.com/nienie/marathon/utils/http"
 )
 
 //URLPing Ping implementation if you want to do a "health check" kind of ping.
@@ -12,6 +18,9 @@ import (
 type URLPing struct {
 	PingAppendString string
 	ExpectedContent  string
+	ExpectedStatus   int
+	Headers          http.Header
+	Client           *http.Client
 }
 
 //NewURLPing ...
@@ -19,29 +28,112 @@ func NewURLPing(pingAppendString, expectedContent string) Ping {
 	return &URLPing{
 		PingAppendString: pingAppendString,
 		ExpectedContent:  expectedContent,
+		ExpectedStatus:   http.StatusOK,
+		Headers:          make(http.Header),
+		Client:           newPingHTTPClient(nil),
 	}
 }
 
+//NewURLPingFromConfig creates a URLPing with PingPath, PingExpectedStatus, PingExpectedContent, PingHeaders and
+//PingTimeout. The ping uses its own transport with the TLS settings of the client.
+func NewURLPingFromConfig(clientConfig config.ClientConfig) Ping {
+	p := &URLPing{
+		PingAppendString: clientConfig.GetPropertyAsString(config.PingPath, config.DefaultPingPath),
+		ExpectedContent:  clientConfig.GetPropertyAsString(config.PingExpectedContent, config.DefaultPingExpectedContent),
+		ExpectedStatus:   clientConfig.GetPropertyAsInteger(config.PingExpectedStatus, config.DefaultPingExpectedStatus),
+		Headers:          parsePingHeaders(clientConfig.GetPropertyAsString(config.PingHeaders, config.DefaultPingHeaders)),
+		Client:           newPingHTTPClient(clientConfig),
+	}
+	return p
+}
+
+func newPingHTTPClient(clientConfig config.ClientConfig) *http.Client {
+	timeout := config.DefaultPingTimeout
+	trans := &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		DisableKeepAlives: true,
+	}
+	if clientConfig != nil {
+		timeout = clientConfig.GetPropertyAsDuration(config.PingTimeout, config.DefaultPingTimeout)
+		tlsConfig, err := httputil.NewTLSConfig(clientConfig)
+		if err != nil {
+			logger.Warnf(nil, "err_msg=invalid tls settings for ping||client=%s||err=%v", clientConfig.GetClientName(), err)
+		}
+		trans.TLSClientConfig = tlsConfig
+	}
+	return &http.Client{
+		Transport: trans,
+		Timeout:   timeout,
+	}
+}
+
+//parsePingHeaders convert a string like "Host:example.com;X-Health-Check:marathon" into http.Header
+func parsePingHeaders(headersStr string) http.Header {
+	headers := make(http.Header)
+	for _, header := range strings.Split(headersStr, ";") {
+		pos := strings.Index(header, ":")
+		if pos <= 0 {
+			continue
+		}
+		headers.Add(strings.TrimSpace(header[:pos]), strings.TrimSpace(header[pos+1:]))
+	}
+	return headers
+}
+
 //IsAlive ...
 func (p *URLPing) IsAlive(svr *server.Server) bool {
-	urlStr := ""
-	urlStr = urlStr + svr.GetScheme() + "://"
-	urlStr = urlStr + svr.GetHostPort()
-	urlStr = urlStr + p.PingAppendString
-	resp, err := http.Get(urlStr)
+	scheme := svr.GetScheme()
+	if len(scheme) == 0 {
+		scheme = "http"
+	}
+	urlStr := scheme + "://" + svr.GetHostPort() + p.PingAppendString
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
 	if err != nil {
 		return false
 	}
-
-	if len(p.ExpectedContent) == 0 {
-		return resp.StatusCode == http.StatusOK
+	for key, values := range p.Headers {
+		for _, val := range values {
+			req.Header.Add(key, val)
+		}
+	}
+	if host := p.Headers.Get("Host"); len(host) > 0 {
+		req.Host = host
 	}
 
+	client := p.Client
+	if client == nil {
+		client = newPingHTTPClient(nil)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
 	defer resp.Body.Close()
-	responseContent, _ := ioutil.ReadAll(resp.Body)
 
-	if p.ExpectedContent == string(responseContent) {
+	expectedStatus := p.ExpectedStatus
+	if expectedStatus <= 0 {
+		expectedStatus = http.StatusOK
+	}
+	if resp.StatusCode != expectedStatus {
+		return false
+	}
+
+	if len(p.ExpectedContent) == 0 {
 		return true
 	}
-	return false
+
+	responseContent, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false
+	}
+	return p.ExpectedContent == string(responseContent)
+}
+
+//GetDefinition ...
+func (p *URLPing) GetDefinition() string {
+	var timeout time.Duration
+	if p.Client != nil {
+		timeout = p.Client.Timeout
+	}
+	return fmt.Sprintf("url|%s|%d|%s|%v|%s", p.PingAppendString, p.ExpectedStatus, p.ExpectedContent, p.Headers, timeout)
 }
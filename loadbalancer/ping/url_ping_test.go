@@ -0,0 +1,80 @@
+package ping
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(scheme string, rawURL string) *server.Server {
+	u, _ := url.Parse(rawURL)
+	port, _ := strconv.Atoi(u.Port())
+	return server.NewServer(scheme, u.Hostname(), port)
+}
+
+//TestParsePingHeaders ...
+func TestParsePingHeaders(t *testing.T) {
+	headers := parsePingHeaders(" Host : example.com;X-Health-Check:marathon;invalid;:empty;X-Health-Check:a:b")
+	assert.Equal(t, "example.com", headers.Get("Host"))
+	assert.Equal(t, []string{"marathon", "a:b"}, headers["X-Health-Check"])
+	assert.Equal(t, 2, len(headers))
+	assert.Equal(t, 0, len(parsePingHeaders("")))
+}
+
+//TestNewURLPingFromConfig ...
+func TestNewURLPingFromConfig(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || r.Host != "example.com" || r.Header.Get("X-Health-Check") != "marathon" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer svr.Close()
+
+	clientConfig := config.NewDefaultClientConfig("ping", nil)
+	clientConfig.SetProperty(config.PingPath, "/health")
+	clientConfig.SetProperty(config.PingExpectedStatus, http.StatusNoContent)
+	clientConfig.SetProperty(config.PingHeaders, "Host:example.com;X-Health-Check:marathon")
+	clientConfig.SetProperty(config.PingTimeout, 2*time.Second)
+	p := NewURLPingFromConfig(clientConfig).(*URLPing)
+	assert.Equal(t, 2*time.Second, p.Client.Timeout)
+	assert.True(t, p.IsAlive(newTestServer("http", svr.URL)))
+
+	clientConfig.SetProperty(config.PingPath, "/other")
+	assert.False(t, NewURLPingFromConfig(clientConfig).IsAlive(newTestServer("http", svr.URL)))
+	//the same settings make the same definition
+	assert.Equal(t, GetDefinition(NewURLPingFromConfig(clientConfig)), GetDefinition(NewURLPingFromConfig(clientConfig)))
+}
+
+//TestURLPingWithTLS ...
+func TestURLPingWithTLS(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	defer svr.Close()
+	caFile, err := ioutil.TempFile("", "ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	caFile.Close()
+
+	clientConfig := config.NewDefaultClientConfig("ping", nil)
+	clientConfig.SetProperty(config.PingExpectedContent, "pong")
+	//the certificate is not trusted
+	assert.False(t, NewURLPingFromConfig(clientConfig).IsAlive(newTestServer("https", svr.URL)))
+
+	clientConfig.SetProperty(config.TLSCAFile, caFile.Name())
+	clientConfig.SetProperty(config.TLSServerName, "example.com")
+	assert.True(t, NewURLPingFromConfig(clientConfig).IsAlive(newTestServer("https", svr.URL)))
+}
@@ -1,6 +1,7 @@
 package loadbalancer
 
 import (
+	"context"
 	"time"
 
 	"github.com/nienie/marathon/server"
@@ -16,6 +17,7 @@ type RetryRule struct {
 	BaseRule
 	SubRule         Rule
 	maxRetryTimeout time.Duration
+	waitQueue       *ServerWaitQueue
 }
 
 //NewRetryRule ...
@@ -43,6 +45,7 @@ func (o *RetryRule) SetMaxRetryMills(maxRetryTime time.Duration) {
 	} else {
 		o.maxRetryTimeout = DefaultMaxRetryTimeout
 	}
+	o.waitQueue = NewServerWaitQueue(o.maxRetryTimeout, 0)
 }
 
 //GetMaxRetryTimeout ...
@@ -55,31 +58,43 @@ func (o *RetryRule) Choose(key interface{}) *server.Server {
 	return o.ChooseFromLoadBalancer(o.GetLoadBalancer(), key)
 }
 
+//ChooseWithContext ...
+func (o *RetryRule) ChooseWithContext(ctx context.Context, key interface{}) *server.Server {
+	return o.ChooseFromLoadBalancerWithContext(ctx, o.GetLoadBalancer(), key)
+}
+
 //ChooseFromLoadBalancer ...
 func (o *RetryRule) ChooseFromLoadBalancer(lb LoadBalancer, key interface{}) *server.Server {
-	deadline := time.Duration(time.Now().UnixNano()) + o.maxRetryTimeout
-
-	answer := o.SubRule.Choose(key)
+	return o.ChooseFromLoadBalancerWithContext(context.Background(), lb, key)
+}
 
-	remainTime := deadline - time.Duration(time.Now().UnixNano())
-	if (answer == nil || !answer.IsAlive()) && remainTime > 0 {
-		timer := time.NewTimer(remainTime)
-		for {
-			select {
-			case <-timer.C:
-				break
-			default:
-				answer = o.SubRule.Choose(key)
-				if answer != nil && answer.IsAlive() {
-					break
-				}
-			}
+//ChooseFromLoadBalancerWithContext if the sub rule returns no available server, it waits for the state changes of
+//the servers up to maxRetryTimeout or the deadline of the context. It does not wait if the caller waits in a
+//ServerWaitQueue itself, e.g. the Context with a WaitQueue.
+func (o *RetryRule) ChooseFromLoadBalancerWithContext(ctx context.Context, lb LoadBalancer, key interface{}) *server.Server {
+	choose := func() *server.Server {
+		answer := o.SubRule.Choose(key)
+		if answer == nil || !answer.GetState().IsAvailable() {
+			return nil
 		}
+		return answer
 	}
 
-	if answer == nil || !answer.IsAlive() {
-		return nil
+	if answer := choose(); answer != nil || lb == nil || isWaitingInServerWaitQueue(ctx) {
+		return answer
 	}
-
+	answer, _ := o.getWaitQueue().Wait(ctx, lb, choose)
 	return answer
 }
+
+//getWaitQueue the RetryRule may be created without SetMaxRetryMills.
+func (o *RetryRule) getWaitQueue() *ServerWaitQueue {
+	if o.waitQueue != nil {
+		return o.waitQueue
+	}
+	maxRetryTimeout := o.maxRetryTimeout
+	if maxRetryTimeout <= 0 {
+		maxRetryTimeout = DefaultMaxRetryTimeout
+	}
+	return NewServerWaitQueue(maxRetryTimeout, 0)
+}
@@ -1,6 +1,8 @@
 package loadbalancer
 
 import (
+	"context"
+
 	"github.com/nienie/marathon/server"
 )
 
@@ -18,6 +20,15 @@ type Rule interface {
 	GetLoadBalancer() LoadBalancer
 }
 
+//ContextRule a Rule which may block, e.g. waiting for an available server, and stops at the cancellation or
+//deadline of the context.
+type ContextRule interface {
+	Rule
+
+	//ChooseWithContext ...
+	ChooseWithContext(ctx context.Context, key interface{}) *server.Server
+}
+
 //BaseRule class that provides a default implementation for setting and getting load balancer
 type BaseRule struct {
 	lb LoadBalancer
@@ -0,0 +1,107 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/server"
+)
+
+type serverWaitQueueKey struct{}
+
+//withServerWaitQueue marks that the caller waits in a ServerWaitQueue itself when no server is available,
+//so the rule should not wait.
+func withServerWaitQueue(ctx context.Context) context.Context {
+	return context.WithValue(ctx, serverWaitQueueKey{}, true)
+}
+
+func isWaitingInServerWaitQueue(ctx context.Context) bool {
+	waiting, _ := ctx.Value(serverWaitQueueKey{}).(bool)
+	return waiting
+}
+
+//chooseServer passes the context to the load balancer if it is a ContextLoadBalancer.
+func chooseServer(ctx context.Context, lb LoadBalancer, key interface{}) *server.Server {
+	if clb, ok := lb.(ContextLoadBalancer); ok {
+		return clb.ChooseServerWithContext(ctx, key)
+	}
+	return lb.ChooseServer(key)
+}
+
+//ServerWaitQueue holds the requests which find no available server, e.g. during a short blip where every server is
+//temp down. The waiters are woken up by the events of the load balancer and choose again, they fail with
+//errors.NoAvailableServer when the queue is full or the max wait expires.
+type ServerWaitQueue struct {
+	maxWait   time.Duration
+	maxLength int64
+	waiting   int64
+}
+
+//NewServerWaitQueue maxLength <= 0 means the length is not limited.
+func NewServerWaitQueue(maxWait time.Duration, maxLength int) *ServerWaitQueue {
+	return &ServerWaitQueue{
+		maxWait:   maxWait,
+		maxLength: int64(maxLength),
+	}
+}
+
+//NewServerWaitQueueFromConfig creates a ServerWaitQueue with ServerWaitTimeout and ServerWaitQueueLength,
+//nil if ServerWaitTimeout is not positive.
+func NewServerWaitQueueFromConfig(clientConfig config.ClientConfig) *ServerWaitQueue {
+	maxWait := clientConfig.GetPropertyAsDuration(config.ServerWaitTimeout, config.DefaultServerWaitTimeout)
+	if maxWait <= 0 {
+		return nil
+	}
+	return NewServerWaitQueue(maxWait,
+		clientConfig.GetPropertyAsInteger(config.ServerWaitQueueLength, config.DefaultServerWaitQueueLength))
+}
+
+//GetMaxWait ...
+func (q *ServerWaitQueue) GetMaxWait() time.Duration {
+	return q.maxWait
+}
+
+//GetWaitingCount returns the number of requests waiting for a server.
+func (q *ServerWaitQueue) GetWaitingCount() int64 {
+	return atomic.LoadInt64(&q.waiting)
+}
+
+//Wait waits for choose to return a server, it tries again whenever the state of a server changes in the load balancer.
+func (q *ServerWaitQueue) Wait(ctx context.Context, lb LoadBalancer, choose func() *server.Server) (*server.Server, error) {
+	waiting := atomic.AddInt64(&q.waiting, 1)
+	defer atomic.AddInt64(&q.waiting, -1)
+	if q.maxLength > 0 && waiting > q.maxLength {
+		return nil, errors.NewClientError(errors.NoAvailableServer,
+			fmt.Errorf("server wait queue is full, length=%d", q.maxLength))
+	}
+
+	listener := NewChannelEventListener(1)
+	defer lb.RemoveEventListener(lb.AddEventListener(listener))
+	if svr := choose(); svr != nil {
+		return svr, nil
+	}
+
+	deadline := time.NewTimer(q.maxWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(readinessCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-listener.Events():
+		case <-ticker.C:
+		case <-deadline.C:
+			return nil, errors.NewClientError(errors.NoAvailableServer,
+				fmt.Errorf("no available server after waiting %s", q.maxWait))
+		case <-ctx.Done():
+			return nil, errors.NewClientError(errors.NoAvailableServer,
+				fmt.Errorf("no available server, err=%v", ctx.Err()))
+		}
+		if svr := choose(); svr != nil {
+			return svr, nil
+		}
+	}
+}
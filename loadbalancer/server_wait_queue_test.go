@@ -0,0 +1,88 @@
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestServerWaitQueue ...
+func TestServerWaitQueue(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), nil, nil, nil)
+	defer lb.Shutdown()
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	lb.AddServers([]*server.Server{svr})
+	lb.MarkServerTempDown(svr)
+	assert.Nil(t, lb.ChooseServer(nil))
+
+	queue := NewServerWaitQueue(50*time.Millisecond, 1)
+	choose := func() *server.Server {
+		return lb.ChooseServer(nil)
+	}
+	_, err := queue.Wait(context.Background(), lb, choose)
+	assert.Equal(t, errors.NoAvailableServer, err.(errors.ClientError).GetErrType())
+
+	queue = NewServerWaitQueue(time.Second, 1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := queue.Wait(context.Background(), lb, choose)
+		assert.Equal(t, errors.NoAvailableServer, err.(errors.ClientError).GetErrType())
+		lb.MarkServerReady(svr)
+	}()
+	start := time.Now()
+	chosen, err := queue.Wait(context.Background(), lb, choose)
+	assert.Nil(t, err)
+	assert.Equal(t, svr, chosen)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	assert.Equal(t, int64(0), queue.GetWaitingCount())
+}
+
+//TestRetryRule ...
+func TestRetryRule(t *testing.T) {
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), NewRetryRule(nil, 50*time.Millisecond), nil, nil)
+	defer lb.Shutdown()
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	lb.AddServers([]*server.Server{svr})
+	lb.MarkServerTempDown(svr)
+
+	start := time.Now()
+	assert.Nil(t, lb.ChooseServer(nil))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		lb.MarkServerReady(svr)
+	}()
+	lb.GetRule().(*RetryRule).SetMaxRetryMills(time.Second)
+	assert.Equal(t, svr, lb.ChooseServer(nil))
+}
+
+//TestRetryRuleWithContext ...
+func TestRetryRuleWithContext(t *testing.T) {
+	rule := &RetryRule{SubRule: NewRoundRobinRule()}
+	lb := NewBaseLoadBalancer(config.NewDefaultClientConfig("test", nil), rule, nil, nil)
+	defer lb.Shutdown()
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	lb.AddServers([]*server.Server{svr})
+	lb.MarkServerTempDown(svr)
+
+	//a RetryRule without SetMaxRetryMills waits for DefaultMaxRetryTimeout, unless the context ends before
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Nil(t, lb.ChooseServerWithContext(ctx, nil))
+	assert.True(t, time.Since(start) < DefaultMaxRetryTimeout)
+
+	//the RetryRule does not wait when the Context waits in its WaitQueue
+	lbContext := NewLoadBalancerContext(config.NewDefaultClientConfig("test", nil), lb)
+	lbContext.WaitQueue = NewServerWaitQueue(50*time.Millisecond, 0)
+	start = time.Now()
+	_, err := lbContext.GetServerFromLoadBalancer(nil, nil)
+	assert.Equal(t, errors.NoAvailableServer, err.(errors.ClientError).GetErrType())
+	assert.True(t, time.Since(start) < DefaultMaxRetryTimeout)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/server"
 )
 
 //Collector ...
@@ -13,3 +14,50 @@ type Collector interface {
 	//RPC ...
 	RPC(context.Context, client.Request, client.Response, error, time.Duration)
 }
+
+//HealthCheckCollector is an optional interface of Collector, it collects the result and latency of the health checks.
+type HealthCheckCollector interface {
+
+	//HealthCheck ...
+	HealthCheck(clientName string, svr *server.Server, isAlive bool, latency time.Duration)
+}
+
+//FallbackCollector is an optional interface of Collector, it collects the fallback invocations,
+//fallbackErr is nil if the fallback succeeds.
+type FallbackCollector interface {
+
+	//Fallback ...
+	Fallback(ctx context.Context, request client.Request, err error, fallbackErr error)
+}
+
+//BulkheadCollector is an optional interface of Collector, it collects the time waited for the permits of the bulkheads,
+//rejected is true if no permit is acquired.
+type BulkheadCollector interface {
+
+	//Bulkhead ...
+	Bulkhead(name string, wait time.Duration, rejected bool)
+}
+
+//RateLimitWaitCollector is an optional interface of Collector, it collects the time waited for the rate limits
+//in the waiting mode, rejected is true if the request gives up waiting.
+type RateLimitWaitCollector interface {
+
+	//RateLimitWait ...
+	RateLimitWait(name string, wait time.Duration, rejected bool)
+}
+
+//QuotaCollector is an optional interface of Collector, it collects the remaining quota of the quota rate limit
+//after every request, and the time when it grows again.
+type QuotaCollector interface {
+
+	//Quota ...
+	Quota(name string, remaining int, resetAt time.Time)
+}
+
+//ConcurrencyLimitCollector is an optional interface of Collector, it collects the limits of the adaptive
+//concurrency rate limit whenever they change.
+type ConcurrencyLimitCollector interface {
+
+	//ConcurrencyLimit ...
+	ConcurrencyLimit(name string, limit int)
+}
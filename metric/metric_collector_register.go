@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/server"
 )
 
 var (
@@ -20,6 +21,16 @@ func RegisterCollectors(cs ...Collector) {
 	metricCollectors = append(metricCollectors, cs...)
 }
 
+//GetCollectors returns a copy of the registered collectors.
+func GetCollectors() []Collector {
+	return append(make([]Collector, 0, len(metricCollectors)), metricCollectors...)
+}
+
+//SetCollectors replaces the registered collectors, e.g. to restore the ones returned by GetCollectors.
+func SetCollectors(cs ...Collector) {
+	metricCollectors = append(make([]Collector, 0, len(cs)), cs...)
+}
+
 //RPC ...
 func RPC(ctx context.Context, request client.Request, response client.Response, err error, costTime time.Duration) {
 	for _, c := range metricCollectors {
@@ -28,3 +39,57 @@ func RPC(ctx context.Context, request client.Request, response client.Response,
 		}
 	}
 }
+
+//HealthCheck ...
+func HealthCheck(clientName string, svr *server.Server, isAlive bool, latency time.Duration) {
+	for _, c := range metricCollectors {
+		if hc, ok := c.(HealthCheckCollector); ok {
+			hc.HealthCheck(clientName, svr, isAlive, latency)
+		}
+	}
+}
+
+//Fallback ...
+func Fallback(ctx context.Context, request client.Request, err error, fallbackErr error) {
+	for _, c := range metricCollectors {
+		if fc, ok := c.(FallbackCollector); ok {
+			fc.Fallback(ctx, request, err, fallbackErr)
+		}
+	}
+}
+
+//Bulkhead ...
+func Bulkhead(name string, wait time.Duration, rejected bool) {
+	for _, c := range metricCollectors {
+		if bc, ok := c.(BulkheadCollector); ok {
+			bc.Bulkhead(name, wait, rejected)
+		}
+	}
+}
+
+//RateLimitWait ...
+func RateLimitWait(name string, wait time.Duration, rejected bool) {
+	for _, c := range metricCollectors {
+		if wc, ok := c.(RateLimitWaitCollector); ok {
+			wc.RateLimitWait(name, wait, rejected)
+		}
+	}
+}
+
+//Quota ...
+func Quota(name string, remaining int, resetAt time.Time) {
+	for _, c := range metricCollectors {
+		if qc, ok := c.(QuotaCollector); ok {
+			qc.Quota(name, remaining, resetAt)
+		}
+	}
+}
+
+//ConcurrencyLimit ...
+func ConcurrencyLimit(name string, limit int) {
+	for _, c := range metricCollectors {
+		if lc, ok := c.(ConcurrencyLimitCollector); ok {
+			lc.ConcurrencyLimit(name, limit)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/nienie/marathon/config"
+)
+
+//LimitAlgorithm adjusts the concurrency limit from the samples of the completed requests, as in Netflix
+//concurrency-limits. It is called with the lock of the limiter held.
+type LimitAlgorithm interface {
+
+	//Update returns the new limit, inflight is the number of requests in flight when the sample is taken.
+	//The limit keeps the fraction of the former updates, so that the small increments add up.
+	Update(limit float64, inflight int, rtt time.Duration, dropped bool) float64
+}
+
+//NewLimitAlgorithm AIMD by default.
+func NewLimitAlgorithm(name string) LimitAlgorithm {
+	switch name {
+	case config.VegasLimitAlgorithm:
+		return NewVegasLimitAlgorithm()
+	case config.Gradient2LimitAlgorithm:
+		return NewGradient2LimitAlgorithm()
+	}
+	return NewAIMDLimitAlgorithm()
+}
+
+//AIMDLimitAlgorithm increases the limit by one if the limit is used, and backs off on the drops.
+type AIMDLimitAlgorithm struct {
+	backoffRatio float64
+}
+
+//NewAIMDLimitAlgorithm ...
+func NewAIMDLimitAlgorithm() *AIMDLimitAlgorithm {
+	return &AIMDLimitAlgorithm{
+		backoffRatio: 0.9,
+	}
+}
+
+//Update ...
+func (a *AIMDLimitAlgorithm) Update(limit float64, inflight int, rtt time.Duration, dropped bool) float64 {
+	if dropped {
+		return limit * a.backoffRatio
+	}
+	//do not grow the limit if the load does not reach it
+	if float64(inflight*2) >= limit {
+		return limit + 1
+	}
+	return limit
+}
+
+//VegasLimitAlgorithm estimates the queue size from the min latency(no load) and the latency of the sample,
+//the limit grows while the queue is small and shrinks when it is large.
+type VegasLimitAlgorithm struct {
+	rttNoLoad  time.Duration
+	samples    int
+	probeEvery int
+}
+
+//NewVegasLimitAlgorithm ...
+func NewVegasLimitAlgorithm() *VegasLimitAlgorithm {
+	return &VegasLimitAlgorithm{
+		probeEvery: 1000,
+	}
+}
+
+//Update ...
+func (a *VegasLimitAlgorithm) Update(limit float64, inflight int, rtt time.Duration, dropped bool) float64 {
+	if rtt <= 0 {
+		return limit
+	}
+	//forget the min latency periodically, in case the upstream gets slower permanently
+	a.samples++
+	if a.samples >= a.probeEvery {
+		a.samples = 0
+		a.rttNoLoad = 0
+	}
+	if a.rttNoLoad == 0 || rtt < a.rttNoLoad {
+		a.rttNoLoad = rtt
+		return limit
+	}
+
+	log := math.Max(1, math.Log10(limit))
+	if dropped {
+		return limit - log
+	}
+	if float64(inflight*2) < limit {
+		return limit
+	}
+	queueSize := math.Ceil(limit * (1 - float64(a.rttNoLoad)/float64(rtt)))
+	alpha, beta := 3*log, 6*log
+	switch {
+	case queueSize <= log:
+		return limit + beta
+	case queueSize < alpha:
+		return limit + log
+	case queueSize > beta:
+		return limit - log
+	}
+	return limit
+}
+
+//Gradient2LimitAlgorithm compares the short term latency with the long term average, the limit shrinks
+//when the latency grows beyond the tolerance.
+type Gradient2LimitAlgorithm struct {
+	longRtt   float64
+	window    float64
+	tolerance float64
+	smoothing float64
+}
+
+//NewGradient2LimitAlgorithm ...
+func NewGradient2LimitAlgorithm() *Gradient2LimitAlgorithm {
+	return &Gradient2LimitAlgorithm{
+		window:    600,
+		tolerance: 1.5,
+		smoothing: 0.2,
+	}
+}
+
+//Update ...
+func (a *Gradient2LimitAlgorithm) Update(limit float64, inflight int, rtt time.Duration, dropped bool) float64 {
+	if rtt <= 0 {
+		return limit
+	}
+	shortRtt := float64(rtt)
+	if a.longRtt == 0 {
+		a.longRtt = shortRtt
+	} else {
+		a.longRtt += (shortRtt - a.longRtt) * 2 / (a.window + 1)
+	}
+	//recover faster after a long period of high latency
+	if a.longRtt/shortRtt > 2 {
+		a.longRtt *= 0.95
+	}
+	if float64(inflight*2) < limit && !dropped {
+		return limit
+	}
+
+	gradient := math.Max(0.5, math.Min(1, a.tolerance*a.longRtt/shortRtt))
+	if dropped {
+		gradient = 0.5
+	}
+	queueSize := math.Sqrt(limit)
+	newLimit := limit*gradient + queueSize
+	return limit*(1-a.smoothing) + newLimit*a.smoothing
+}
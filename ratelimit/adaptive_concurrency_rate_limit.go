@@ -0,0 +1,203 @@
+package ratelimit
+
+import (
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/metric"
+	"github.com/nienie/marathon/server"
+)
+
+var (
+	adaptiveLimiterLock sync.RWMutex
+	adaptiveLimiters    map[string]*AdaptiveLimiter
+)
+
+func init() {
+	adaptiveLimiters = make(map[string]*AdaptiveLimiter)
+	adaptiveLimiterLock = sync.RWMutex{}
+}
+
+//AdaptiveLimiter a concurrency limit adjusted by a LimitAlgorithm.
+type AdaptiveLimiter struct {
+	name      string
+	algorithm LimitAlgorithm
+	minLimit  float64
+	maxLimit  float64
+	timeout   time.Duration
+
+	lock     *sync.Mutex
+	limit    float64
+	inflight int
+}
+
+//NewAdaptiveLimiter timeout <= 0 means the latency never makes a drop.
+func NewAdaptiveLimiter(name string, algorithm LimitAlgorithm, initialLimit, minLimit, maxLimit int,
+	timeout time.Duration) *AdaptiveLimiter {
+	if minLimit <= 0 {
+		minLimit = 1
+	}
+	if maxLimit < minLimit {
+		maxLimit = minLimit
+	}
+	l := &AdaptiveLimiter{
+		name:      name,
+		algorithm: algorithm,
+		minLimit:  float64(minLimit),
+		maxLimit:  float64(maxLimit),
+		timeout:   timeout,
+		lock:      &sync.Mutex{},
+	}
+	l.limit = l.clamp(float64(initialLimit))
+	metric.ConcurrencyLimit(name, l.GetLimit())
+	return l
+}
+
+//GetLimit ...
+func (l *AdaptiveLimiter) GetLimit() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return int(l.limit)
+}
+
+//GetInflight ...
+func (l *AdaptiveLimiter) GetInflight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inflight
+}
+
+//TryAcquire false if the requests in flight reach the limit, otherwise the request must be completed
+//with OnSample or Release.
+func (l *AdaptiveLimiter) TryAcquire() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inflight >= int(l.limit) {
+		return false
+	}
+	l.inflight++
+	return true
+}
+
+//Release completes a request without a sample, e.g. it fails for reasons unrelated to the load.
+func (l *AdaptiveLimiter) Release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inflight > 0 {
+		l.inflight--
+	}
+}
+
+//OnSample completes a request and adjusts the limit, the requests slower than the timeout are dropped.
+func (l *AdaptiveLimiter) OnSample(rtt time.Duration, dropped bool) {
+	if l.timeout > 0 && rtt > l.timeout {
+		dropped = true
+	}
+	l.lock.Lock()
+	inflight := l.inflight
+	if l.inflight > 0 {
+		l.inflight--
+	}
+	oldLimit := int(l.limit)
+	l.limit = l.clamp(l.algorithm.Update(l.limit, inflight, rtt, dropped))
+	newLimit := int(l.limit)
+	l.lock.Unlock()
+
+	if newLimit != oldLimit {
+		metric.ConcurrencyLimit(l.name, newLimit)
+	}
+}
+
+func (l *AdaptiveLimiter) clamp(limit float64) float64 {
+	if math.IsNaN(limit) {
+		return l.minLimit
+	}
+	return math.Max(l.minLimit, math.Min(l.maxLimit, limit))
+}
+
+//AdaptiveConcurrencyRateLimit limits the concurrent requests of the client or of every server with an adaptive
+//limit, which grows when the upstream is fast and shrinks when the latency grows or the requests are dropped.
+type AdaptiveConcurrencyRateLimit struct{}
+
+//NewAdaptiveConcurrencyRateLimit ...
+func NewAdaptiveConcurrencyRateLimit() *AdaptiveConcurrencyRateLimit {
+	return &AdaptiveConcurrencyRateLimit{}
+}
+
+//Allow ...
+func (l *AdaptiveConcurrencyRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	limiter := getAdaptiveLimiter(serverStats, requestConfig)
+	if limiter == nil {
+		return true
+	}
+	return limiter.TryAcquire()
+}
+
+//OnComplete ...
+func (l *AdaptiveConcurrencyRateLimit) OnComplete(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig,
+	response client.Response, latency time.Duration, err error) {
+	limiter := getAdaptiveLimiter(serverStats, requestConfig)
+	if limiter == nil {
+		return
+	}
+	switch {
+	case IsOverloaded(response, err):
+		limiter.OnSample(latency, true)
+	case err == nil:
+		limiter.OnSample(latency, false)
+	default:
+		limiter.Release()
+	}
+}
+
+//GetAdaptiveLimiter returns the limiter of the client, or of the server if AdaptiveConcurrencyScope is server,
+//nil if it has not been created.
+func GetAdaptiveLimiter(clientName string, svr *server.Server) *AdaptiveLimiter {
+	key := clientName
+	if svr != nil {
+		key += "@" + svr.GetHostPort()
+	}
+	adaptiveLimiterLock.RLock()
+	defer adaptiveLimiterLock.RUnlock()
+	return adaptiveLimiters[key]
+}
+
+func getAdaptiveLimiter(serverStats *server.Stats, requestConfig config.ClientConfig) *AdaptiveLimiter {
+	if requestConfig == nil ||
+		!requestConfig.GetPropertyAsBool(config.AdaptiveConcurrencyRateLimitSwitch, config.DefaultAdaptiveConcurrencyRateLimitSwitch) {
+		return nil
+	}
+
+	key := requestConfig.GetClientName()
+	if requestConfig.GetPropertyAsString(config.AdaptiveConcurrencyScope, config.DefaultAdaptiveConcurrencyScope) == config.ServerLimitScope {
+		if serverStats == nil || serverStats.Server == nil {
+			return nil
+		}
+		key += "@" + serverStats.Server.GetHostPort()
+	}
+
+	adaptiveLimiterLock.RLock()
+	limiter, ok := adaptiveLimiters[key]
+	adaptiveLimiterLock.RUnlock()
+	if ok {
+		return limiter
+	}
+	adaptiveLimiterLock.Lock()
+	defer adaptiveLimiterLock.Unlock()
+	if limiter, ok = adaptiveLimiters[key]; ok {
+		return limiter
+	}
+	limiter = NewAdaptiveLimiter(key,
+		NewLimitAlgorithm(requestConfig.GetPropertyAsString(config.AdaptiveConcurrencyAlgorithm, config.DefaultAdaptiveConcurrencyAlgorithm)),
+		requestConfig.GetPropertyAsInteger(config.AdaptiveConcurrencyInitialLimit, config.DefaultAdaptiveConcurrencyInitialLimit),
+		requestConfig.GetPropertyAsInteger(config.AdaptiveConcurrencyMinLimit, config.DefaultAdaptiveConcurrencyMinLimit),
+		requestConfig.GetPropertyAsInteger(config.AdaptiveConcurrencyMaxLimit, config.DefaultAdaptiveConcurrencyMaxLimit),
+		requestConfig.GetPropertyAsDuration(config.AdaptiveConcurrencyTimeout, config.DefaultAdaptiveConcurrencyTimeout),
+	)
+	adaptiveLimiters[key] = limiter
+	return limiter
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type rejectingRateLimit struct {
+	clientName string
+}
+
+func (l *rejectingRateLimit) Allow(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	return requestConfig == nil || requestConfig.GetClientName() != l.clientName
+}
+
+//TestAdaptiveLimiter ...
+func TestAdaptiveLimiter(t *testing.T) {
+	limiter := NewAdaptiveLimiter("test", NewAIMDLimitAlgorithm(), 2, 1, 10, 100*time.Millisecond)
+	assert.True(t, limiter.TryAcquire())
+	assert.True(t, limiter.TryAcquire())
+	assert.False(t, limiter.TryAcquire())
+
+	//the limit is used, so it grows
+	limiter.OnSample(10*time.Millisecond, false)
+	assert.Equal(t, 3, limiter.GetLimit())
+	assert.Equal(t, 1, limiter.GetInflight())
+	//a slow request is a drop
+	limiter.OnSample(200*time.Millisecond, false)
+	assert.Equal(t, 2, limiter.GetLimit())
+	assert.Equal(t, 0, limiter.GetInflight())
+
+	for i := 0; i < 10; i++ {
+		limiter.OnSample(0, true)
+	}
+	assert.Equal(t, 1, limiter.GetLimit())
+}
+
+//TestAdaptiveLimiterGrowth ...
+func TestAdaptiveLimiterGrowth(t *testing.T) {
+	for _, name := range []string{config.AIMDLimitAlgorithm, config.VegasLimitAlgorithm, config.Gradient2LimitAlgorithm} {
+		limiter := NewAdaptiveLimiter("test", NewLimitAlgorithm(name), 5, 1, 100, 0)
+		//a small limit grows by fractions with Gradient2, they must add up
+		for i := 0; i < 50; i++ {
+			for limiter.TryAcquire() {
+			}
+			limiter.OnSample(10*time.Millisecond, false)
+		}
+		assert.True(t, limiter.GetLimit() > 5, name)
+	}
+}
+
+//TestLimitAlgorithm ...
+func TestLimitAlgorithm(t *testing.T) {
+	vegas := NewLimitAlgorithm(config.VegasLimitAlgorithm)
+	assert.Equal(t, float64(20), vegas.Update(20, 20, 10*time.Millisecond, false))
+	//no queue
+	assert.True(t, vegas.Update(20, 20, 10*time.Millisecond, false) > 20)
+	//a long queue
+	assert.True(t, vegas.Update(20, 20, 100*time.Millisecond, false) < 20)
+	assert.True(t, vegas.Update(20, 20, 10*time.Millisecond, true) < 20)
+
+	gradient := NewLimitAlgorithm(config.Gradient2LimitAlgorithm)
+	for i := 0; i < 100; i++ {
+		gradient.Update(20, 20, 10*time.Millisecond, false)
+	}
+	assert.True(t, gradient.Update(20, 20, 10*time.Millisecond, false) > 20)
+	assert.True(t, gradient.Update(20, 20, 100*time.Millisecond, false) < 20)
+	//the load does not reach the limit
+	assert.Equal(t, float64(20), gradient.Update(20, 1, 100*time.Millisecond, false))
+
+	aimd := NewLimitAlgorithm("")
+	assert.Equal(t, float64(21), aimd.Update(20, 10, time.Millisecond, false))
+	assert.Equal(t, float64(20), aimd.Update(20, 9, time.Millisecond, false))
+}
+
+//TestAdaptiveConcurrencyRateLimit ...
+func TestAdaptiveConcurrencyRateLimit(t *testing.T) {
+	adaptiveLimiterLock.Lock()
+	adaptiveLimiters = make(map[string]*AdaptiveLimiter)
+	adaptiveLimiterLock.Unlock()
+
+	requestConfig := config.NewDefaultClientConfig("adaptive", nil)
+	requestConfig.SetProperty(config.AdaptiveConcurrencyRateLimitSwitch, true)
+	requestConfig.SetProperty(config.AdaptiveConcurrencyScope, config.ServerLimitScope)
+	requestConfig.SetProperty(config.AdaptiveConcurrencyInitialLimit, 1)
+	uri, _ := url.Parse("http://127.0.0.1:8080/adaptive")
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	serverStats := server.NewDefaultServerStats()
+	serverStats.Initialize(svr)
+
+	assert.True(t, Allow(uri, serverStats, requestConfig))
+	assert.False(t, Allow(uri, serverStats, requestConfig))
+	limiter := GetAdaptiveLimiter("adaptive", svr)
+	assert.NotNil(t, limiter)
+	assert.Equal(t, 1, limiter.GetInflight())
+
+	//the errors unrelated to the load release the request without a sample
+	OnComplete(uri, serverStats, requestConfig, nil, time.Millisecond, errors.NewClientError(errors.ConnectException, nil))
+	assert.Equal(t, 0, limiter.GetInflight())
+	assert.Equal(t, 1, limiter.GetLimit())
+
+	assert.True(t, Allow(uri, serverStats, requestConfig))
+	OnComplete(uri, serverStats, requestConfig, nil, time.Millisecond, nil)
+	assert.Equal(t, 2, limiter.GetLimit())
+
+	//the request rejected by a later rate limit is released
+	RegisterRateLimit(&rejectingRateLimit{clientName: "rejected"})
+	rejectedConfig := config.NewDefaultClientConfig("rejected", nil)
+	rejectedConfig.SetProperty(config.AdaptiveConcurrencyRateLimitSwitch, true)
+	assert.False(t, Allow(uri, serverStats, rejectedConfig))
+	assert.Equal(t, 0, GetAdaptiveLimiter("rejected", nil).GetInflight())
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//DefaultBucketIdleTimeout the buckets not used for the timeout are evicted.
+	DefaultBucketIdleTimeout = time.Minute
+	//DefaultMaxBuckets the max number of buckets of an algorithm, the least recently used ones are evicted when it is reached.
+	DefaultMaxBuckets = 100000
+
+	bucketCacheShards   = 64
+	bucketEvictSamples  = 5
+	bucketTouchFraction = 8
+)
+
+//bucketEntry the access time is updated lazily, at most once every 1/bucketTouchFraction of the idle timeout.
+type bucketEntry struct {
+	bucket     interface{}
+	accessTime int64
+}
+
+//bucketCacheShard the idle buckets of the shard are swept when a bucket is added, at most once every idle timeout.
+type bucketCacheShard struct {
+	lock      sync.RWMutex
+	buckets   map[string]*bucketEntry
+	nextSweep int64
+}
+
+//bucketCache keeps the buckets of the requested urls, a bucket evicted is created again when the url is requested,
+//so the memory is bounded even if the urls have ids in the path.
+//The lookups only take the read lock of a shard, there is no background task. It is not a cache.TimedCache,
+//which takes the write lock of the whole cache to refresh an item on every lookup, cannot bound its size
+//and runs a janitor goroutine ticking every 100ms for the cache of every algorithm.
+type bucketCache struct {
+	idleTimeout int64
+	maxBuckets  int //of a shard
+	shards      []*bucketCacheShard
+}
+
+func newBucketCache() *bucketCache {
+	return newBucketCacheWithSize(DefaultBucketIdleTimeout, DefaultMaxBuckets)
+}
+
+func newBucketCacheWithSize(idleTimeout time.Duration, maxBuckets int) *bucketCache {
+	shards := bucketCacheShards
+	if maxBuckets < shards {
+		shards = maxBuckets
+	}
+	if shards <= 0 {
+		shards = 1
+	}
+	c := &bucketCache{
+		idleTimeout: int64(idleTimeout),
+		maxBuckets:  maxBuckets / shards,
+		shards:      make([]*bucketCacheShard, shards),
+	}
+	if c.maxBuckets <= 0 {
+		c.maxBuckets = 1
+	}
+	for i := range c.shards {
+		c.shards[i] = &bucketCacheShard{
+			buckets: make(map[string]*bucketEntry),
+		}
+	}
+	return c
+}
+
+func (c *bucketCache) getShard(key string) *bucketCacheShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+func (c *bucketCache) getOrCreate(key string, create func() (interface{}, error)) (interface{}, error) {
+	now := time.Now().UnixNano()
+	shard := c.getShard(key)
+	shard.lock.RLock()
+	entry, ok := shard.buckets[key]
+	shard.lock.RUnlock()
+	if ok && c.touch(entry, now) {
+		return entry.bucket, nil
+	}
+
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	if entry, ok = shard.buckets[key]; ok && c.touch(entry, now) {
+		return entry.bucket, nil
+	}
+	bucket, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.sweepLocked(shard, now)
+	if _, ok = shard.buckets[key]; !ok && len(shard.buckets) >= c.maxBuckets {
+		c.evictLocked(shard)
+	}
+	shard.buckets[key] = &bucketEntry{
+		bucket:     bucket,
+		accessTime: now,
+	}
+	return bucket, nil
+}
+
+//touch false if the bucket is idle for the timeout.
+func (c *bucketCache) touch(entry *bucketEntry, now int64) bool {
+	accessTime := atomic.LoadInt64(&entry.accessTime)
+	if now-accessTime > c.idleTimeout {
+		return false
+	}
+	if now-accessTime > c.idleTimeout/bucketTouchFraction {
+		atomic.StoreInt64(&entry.accessTime, now)
+	}
+	return true
+}
+
+func (c *bucketCache) sweepLocked(shard *bucketCacheShard, now int64) {
+	if now < shard.nextSweep {
+		return
+	}
+	shard.nextSweep = now + c.idleTimeout
+	for key, entry := range shard.buckets {
+		if now-atomic.LoadInt64(&entry.accessTime) > c.idleTimeout {
+			delete(shard.buckets, key)
+		}
+	}
+}
+
+//evictLocked evicts the least recently used one among a few random buckets.
+func (c *bucketCache) evictLocked(shard *bucketCacheShard) {
+	var (
+		evictKey        string
+		evictAccessTime int64
+		samples         int
+	)
+	//the iteration order of a map is random
+	for key, entry := range shard.buckets {
+		accessTime := atomic.LoadInt64(&entry.accessTime)
+		if samples == 0 || accessTime < evictAccessTime {
+			evictKey, evictAccessTime = key, accessTime
+		}
+		samples++
+		if samples >= bucketEvictSamples {
+			break
+		}
+	}
+	if samples > 0 {
+		delete(shard.buckets, evictKey)
+	}
+}
+
+func (c *bucketCache) len() int {
+	n := 0
+	for _, shard := range c.shards {
+		shard.lock.RLock()
+		n += len(shard.buckets)
+		shard.lock.RUnlock()
+	}
+	return n
+}
@@ -0,0 +1,213 @@
+package ratelimit
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/server"
+)
+
+const (
+	//DefaultStoreRetryInterval the store is not retried within the interval after it fails.
+	DefaultStoreRetryInterval = time.Second
+)
+
+var (
+	distributedBuckets *bucketCache
+)
+
+func init() {
+	distributedBuckets = newBucketCache()
+}
+
+//DistributedBucket takes the tokens of a shared bucket from the Store in batches, so that most of the requests
+//do not need a round trip. It limits the requests locally with the share of the instance when the store fails
+//or does not answer within the timeout.
+//Only one request takes the tokens from the store at a time, the others wait for it without holding the lock.
+type DistributedBucket struct {
+	store            Store
+	key              string
+	emissionInterval time.Duration
+	burst            int
+	batchSize        int
+	batchTTL         time.Duration
+	timeout          time.Duration
+	local            *GCRA
+
+	lock       sync.Mutex
+	tokens     int
+	expireAt   time.Time
+	emptyUntil time.Time
+	retryAt    time.Time
+	refill     chan struct{} //closed when the refill in flight is done, nil if there is none
+}
+
+//NewDistributedBucket count tokens are put into the shared bucket every interval, the instances share the rate
+//equally when the store fails.
+func NewDistributedBucket(store Store, key string, capacity int, interval time.Duration, count int, batchSize int,
+	instances int) *DistributedBucket {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	if count <= 0 {
+		count = 1
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if instances <= 0 {
+		instances = 1
+	}
+	localBurst := capacity / instances
+	if localBurst <= 0 {
+		localBurst = 1
+	}
+	emissionInterval := interval / time.Duration(count)
+	return &DistributedBucket{
+		store:            store,
+		key:              key,
+		emissionInterval: emissionInterval,
+		burst:            capacity,
+		batchSize:        batchSize,
+		batchTTL:         interval,
+		timeout:          config.DefaultDistributedRateLimitTimeout,
+		local:            NewGCRA(emissionInterval*time.Duration(instances), localBurst),
+	}
+}
+
+//SetTimeout the deadline of a call to the store.
+func (b *DistributedBucket) SetTimeout(timeout time.Duration) *DistributedBucket {
+	if timeout > 0 {
+		b.timeout = timeout
+	}
+	return b
+}
+
+//GetToken ...
+func (b *DistributedBucket) GetToken() bool {
+	deadline := time.Now().Add(b.timeout)
+	for {
+		b.lock.Lock()
+		now := time.Now()
+		if b.tokens > 0 && now.Before(b.expireAt) {
+			b.tokens--
+			b.lock.Unlock()
+			return true
+		}
+		if now.Before(b.retryAt) {
+			b.lock.Unlock()
+			return b.local.AllowAt(now)
+		}
+		if now.Before(b.emptyUntil) {
+			b.lock.Unlock()
+			return false
+		}
+		if refill := b.refill; refill != nil {
+			b.lock.Unlock()
+			if !b.waitRefill(refill, deadline) {
+				return b.local.AllowAt(time.Now())
+			}
+			continue
+		}
+		refill := make(chan struct{})
+		b.refill = refill
+		b.lock.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+		taken, err := b.store.Take(ctx, b.key, b.batchSize, b.emissionInterval, b.burst)
+		cancel()
+
+		b.lock.Lock()
+		b.refill = nil
+		close(refill)
+		now = time.Now()
+		if err != nil {
+			b.retryAt = now.Add(DefaultStoreRetryInterval)
+			b.lock.Unlock()
+			logger.Warnf(nil, "err_msg=rate limit store is unavailable, limit locally||key=%s||err=%v", b.key, err)
+			return b.local.AllowAt(now)
+		}
+		if taken <= 0 {
+			//no token before the next one is put
+			b.tokens = 0
+			b.emptyUntil = now.Add(b.emissionInterval)
+			b.lock.Unlock()
+			return false
+		}
+		b.tokens = taken - 1
+		b.expireAt = now.Add(b.batchTTL)
+		b.lock.Unlock()
+		return true
+	}
+}
+
+//waitRefill false if the refill in flight is not done before the deadline.
+func (b *DistributedBucket) waitRefill(refill chan struct{}, deadline time.Time) bool {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return false
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-refill:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+//DistributedRateLimit limits the requests of the instances of a client together with a bucket in a shared Store,
+//e.g. a quota of a third party api.
+type DistributedRateLimit struct{}
+
+//NewDistributedRateLimit ...
+func NewDistributedRateLimit() *DistributedRateLimit {
+	return &DistributedRateLimit{}
+}
+
+//Allow ...
+func (l *DistributedRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	bucket := getDistributedBucket(requestConfig)
+	if bucket == nil {
+		return true
+	}
+	return bucket.GetToken()
+}
+
+func getDistributedBucket(requestConfig config.ClientConfig) *DistributedBucket {
+	if requestConfig == nil ||
+		!requestConfig.GetPropertyAsBool(config.DistributedRateLimitSwitch, config.DefaultDistributedRateLimitSwitch) {
+		return nil
+	}
+
+	storeName := requestConfig.GetPropertyAsString(config.DistributedRateLimitStore, config.DefaultDistributedRateLimitStore)
+	store := GetStore(storeName)
+	if store == nil {
+		logger.Warnf(nil, "err_msg=rate limit store is not registered||client=%s||store=%s",
+			requestConfig.GetClientName(), storeName)
+		return nil
+	}
+	key := requestConfig.GetPropertyAsString(config.DistributedRateLimitKey, config.DefaultDistributedRateLimitKey)
+	if len(key) == 0 {
+		key = requestConfig.GetClientName()
+	}
+	bucket, err := distributedBuckets.getOrCreate(storeName+"|"+key, func() (interface{}, error) {
+		return NewDistributedBucket(store, key,
+			requestConfig.GetPropertyAsInteger(config.DistributedRateLimitCapacity, config.DefaultDistributedRateLimitCapacity),
+			requestConfig.GetPropertyAsDuration(config.DistributedRateLimitInterval, config.DefaultDistributedRateLimitInterval),
+			requestConfig.GetPropertyAsInteger(config.DistributedRateLimitCount, config.DefaultDistributedRateLimitCount),
+			requestConfig.GetPropertyAsInteger(config.DistributedRateLimitBatchSize, config.DefaultDistributedRateLimitBatchSize),
+			requestConfig.GetPropertyAsInteger(config.DistributedRateLimitInstances, config.DefaultDistributedRateLimitInstances),
+		).SetTimeout(requestConfig.GetPropertyAsDuration(config.DistributedRateLimitTimeout,
+			config.DefaultDistributedRateLimitTimeout)), nil
+	})
+	if err != nil {
+		return nil
+	}
+	return bucket.(*DistributedBucket)
+}
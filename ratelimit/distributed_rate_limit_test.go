@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingStore struct {
+	store Store
+	takes int
+	err   error
+}
+
+func (s *countingStore) Take(ctx context.Context, key string, n int, emissionInterval time.Duration, burst int) (
+	int, error) {
+	s.takes++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.store.Take(ctx, key, n, emissionInterval, burst)
+}
+
+//hangingStore does not answer until the context is done.
+type hangingStore struct {
+	takes int32
+}
+
+func (s *hangingStore) Take(ctx context.Context, key string, n int, emissionInterval time.Duration, burst int) (
+	int, error) {
+	atomic.AddInt32(&s.takes, 1)
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+type fakeRedisClient struct {
+	result interface{}
+	err    error
+	keys   []string
+	args   []interface{}
+}
+
+func (c *fakeRedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	c.keys, c.args = keys, args
+	return c.result, c.err
+}
+
+//TestGCRATake ...
+func TestGCRATake(t *testing.T) {
+	gcra := NewGCRA(10*time.Millisecond, 5)
+	now := time.Now()
+	assert.Equal(t, 3, gcra.TakeAt(now, 3))
+	assert.Equal(t, 2, gcra.TakeAt(now, 3))
+	assert.Equal(t, 0, gcra.TakeAt(now, 3))
+	assert.Equal(t, 1, gcra.TakeAt(now.Add(10*time.Millisecond), 3))
+}
+
+//TestDistributedBucket ...
+func TestDistributedBucket(t *testing.T) {
+	store := &countingStore{store: NewMemoryStore()}
+	//two instances share 10 tokens
+	first := NewDistributedBucket(store, "quota", 10, time.Hour, 10, 4, 2)
+	second := NewDistributedBucket(store, "quota", 10, time.Hour, 10, 4, 2)
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if first.GetToken() {
+			allowed++
+		}
+		if second.GetToken() {
+			allowed++
+		}
+	}
+	assert.Equal(t, 10, allowed)
+	//the tokens are taken in batches
+	assert.True(t, store.takes < 10)
+
+	//the local share of the instance when the store fails
+	store = &countingStore{err: fmt.Errorf("connection refused")}
+	bucket := NewDistributedBucket(store, "quota", 10, time.Hour, 10, 4, 2)
+	allowed = 0
+	for i := 0; i < 10; i++ {
+		if bucket.GetToken() {
+			allowed++
+		}
+	}
+	assert.Equal(t, 5, allowed)
+	//the store is not retried at once
+	assert.Equal(t, 1, store.takes)
+}
+
+//TestDistributedBucketHangingStore ...
+func TestDistributedBucketHangingStore(t *testing.T) {
+	store := &hangingStore{}
+	bucket := NewDistributedBucket(store, "quota", 10, time.Hour, 10, 4, 2).SetTimeout(50 * time.Millisecond)
+	start := time.Now()
+	var (
+		wg      sync.WaitGroup
+		allowed int32
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if bucket.GetToken() {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	//the requests fall back to the local share of the instance after the timeout
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&allowed))
+	//only one request calls the store
+	assert.Equal(t, int32(1), atomic.LoadInt32(&store.takes))
+}
+
+//TestRedisStore ...
+func TestRedisStore(t *testing.T) {
+	client := &fakeRedisClient{result: int64(3)}
+	store := NewRedisStore(client).SetKeyPrefix("test:")
+	taken, err := store.Take(context.Background(), "quota", 5, 10*time.Millisecond, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, taken)
+	assert.Equal(t, []string{"test:quota"}, client.keys)
+	assert.Equal(t, []interface{}{int64(10000), 20, 5}, client.args)
+
+	client.result = "x"
+	_, err = store.Take(context.Background(), "quota", 5, 10*time.Millisecond, 20)
+	assert.NotNil(t, err)
+	client.err = fmt.Errorf("timeout")
+	_, err = store.Take(context.Background(), "quota", 5, 10*time.Millisecond, 20)
+	assert.NotNil(t, err)
+}
+
+//TestDistributedRateLimit ...
+func TestDistributedRateLimit(t *testing.T) {
+	distributedBuckets = newBucketCache()
+	RegisterStore("memory", NewMemoryStore())
+
+	requestConfig := config.NewDefaultClientConfig("distributed", nil)
+	requestConfig.SetProperty(config.DistributedRateLimitSwitch, true)
+	requestConfig.SetProperty(config.DistributedRateLimitStore, "memory")
+	requestConfig.SetProperty(config.DistributedRateLimitCapacity, 3)
+	requestConfig.SetProperty(config.DistributedRateLimitInterval, time.Hour)
+	requestConfig.SetProperty(config.DistributedRateLimitCount, 3)
+	uri, _ := url.Parse("http://127.0.0.1:8080/distributed")
+	for i := 0; i < 3; i++ {
+		assert.True(t, Allow(uri, nil, requestConfig))
+	}
+	assert.False(t, Allow(uri, nil, requestConfig))
+
+	//not limited if the store is not registered
+	requestConfig.SetProperty(config.DistributedRateLimitStore, "unknown")
+	assert.True(t, Allow(uri, nil, requestConfig))
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//GCRA the generic cell rate algorithm(virtual scheduling). Instead of counting the tokens, it keeps the
+//theoretical arrival time(TAT) of the next request, a request is allowed if it does not arrive earlier than
+//TAT - burstTolerance. It is refilled lazily and lock free, so no goroutine is needed for a bucket.
+type GCRA struct {
+	emissionInterval int64
+	burstTolerance   int64
+	tat              int64
+}
+
+//NewGCRA one request every emissionInterval, and at most burst requests at once.
+func NewGCRA(emissionInterval time.Duration, burst int) *GCRA {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &GCRA{
+		emissionInterval: int64(emissionInterval),
+		burstTolerance:   int64(emissionInterval) * int64(burst-1),
+	}
+}
+
+//Allow ...
+func (g *GCRA) Allow() bool {
+	return g.AllowAt(time.Now())
+}
+
+//AllowAt ...
+func (g *GCRA) AllowAt(now time.Time) bool {
+	_, ok := g.ReserveAt(now, 0)
+	return ok
+}
+
+//Reserve ...
+func (g *GCRA) Reserve(maxWait time.Duration) (time.Duration, bool) {
+	return g.ReserveAt(time.Now(), maxWait)
+}
+
+//ReserveAt takes a request which is allowed after the delay, ok is false if the delay is longer than maxWait.
+func (g *GCRA) ReserveAt(now time.Time, maxWait time.Duration) (delay time.Duration, ok bool) {
+	t := now.UnixNano()
+	for {
+		tat := atomic.LoadInt64(&g.tat)
+		newTat := tat
+		if newTat < t {
+			newTat = t
+		}
+		wait := newTat - g.burstTolerance - t
+		if wait < 0 {
+			wait = 0
+		}
+		if wait > int64(maxWait) {
+			return 0, false
+		}
+		if atomic.CompareAndSwapInt64(&g.tat, tat, newTat+g.emissionInterval) {
+			return time.Duration(wait), true
+		}
+	}
+}
+
+//TakeAt takes at most n requests at once, it returns the number of requests taken.
+func (g *GCRA) TakeAt(now time.Time, n int) int {
+	t := now.UnixNano()
+	for {
+		tat := atomic.LoadInt64(&g.tat)
+		newTat := tat
+		if newTat < t {
+			newTat = t
+		}
+		if newTat-t > g.burstTolerance {
+			return 0
+		}
+		taken := int64(n)
+		if g.emissionInterval > 0 {
+			if available := (t+g.burstTolerance-newTat)/g.emissionInterval + 1; available < taken {
+				taken = available
+			}
+		}
+		if taken <= 0 {
+			return 0
+		}
+		if atomic.CompareAndSwapInt64(&g.tat, tat, newTat+taken*g.emissionInterval) {
+			return int(taken)
+		}
+	}
+}
+
+//Cancel gives back a request taken by ReserveAt. It is approximate, the request is given back to the end of
+//the schedule, which may not be the one cancelled.
+func (g *GCRA) Cancel() {
+	atomic.AddInt64(&g.tat, -g.emissionInterval)
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestGCRA ...
+func TestGCRA(t *testing.T) {
+	gcra := NewGCRA(10*time.Millisecond, 3)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.True(t, gcra.AllowAt(now))
+	}
+	assert.False(t, gcra.AllowAt(now))
+	assert.False(t, gcra.AllowAt(now.Add(9*time.Millisecond)))
+	assert.True(t, gcra.AllowAt(now.Add(10*time.Millisecond)))
+	assert.False(t, gcra.AllowAt(now.Add(10*time.Millisecond)))
+
+	//the bucket is refilled lazily, but never beyond the burst
+	later := now.Add(time.Second)
+	for i := 0; i < 3; i++ {
+		assert.True(t, gcra.AllowAt(later))
+	}
+	assert.False(t, gcra.AllowAt(later))
+}
+
+//TestGCRAConcurrently ...
+func TestGCRAConcurrently(t *testing.T) {
+	gcra := NewGCRA(time.Hour, 100)
+	now := time.Now()
+	var (
+		wg      sync.WaitGroup
+		lock    sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if gcra.AllowAt(now) {
+					lock.Lock()
+					allowed++
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, allowed)
+}
+
+//TestTokenBucket ...
+func TestTokenBucket(t *testing.T) {
+	_, err := NewTokenBucket(10, 0, 1)
+	assert.NotNil(t, err)
+
+	bucket, err := NewTokenBucket(5, time.Hour, 2)
+	assert.Nil(t, err)
+	for i := 0; i < 5; i++ {
+		assert.True(t, bucket.GetToken())
+	}
+	assert.False(t, bucket.GetToken())
+
+	leaky, err := NewLeakyBucket(2, time.Hour)
+	assert.Nil(t, err)
+	assert.True(t, leaky.Put())
+	assert.True(t, leaky.Put())
+	assert.False(t, leaky.Put())
+}
+
+//TestBucketCache ...
+func TestBucketCache(t *testing.T) {
+	buckets := newBucketCacheWithSize(time.Minute, 10)
+	first, err := buckets.getOrCreate("/users/0", func() (interface{}, error) {
+		return NewTokenBucket(1, time.Hour, 1)
+	})
+	assert.Nil(t, err)
+	same, _ := buckets.getOrCreate("/users/0", func() (interface{}, error) {
+		return nil, fmt.Errorf("should not be created")
+	})
+	assert.True(t, first == same)
+
+	for i := 1; i < 100; i++ {
+		buckets.getOrCreate(fmt.Sprintf("/users/%d", i), func() (interface{}, error) {
+			return NewTokenBucket(1, time.Hour, 1)
+		})
+	}
+	assert.True(t, buckets.len() <= 10)
+	assert.True(t, buckets.len() > 0)
+
+	//the idle buckets are created again
+	buckets = newBucketCacheWithSize(50*time.Millisecond, 10)
+	first, _ = buckets.getOrCreate("/users/0", func() (interface{}, error) {
+		return NewTokenBucket(1, time.Hour, 1)
+	})
+	time.Sleep(100 * time.Millisecond)
+	created, _ := buckets.getOrCreate("/users/0", func() (interface{}, error) {
+		return NewTokenBucket(1, time.Hour, 1)
+	})
+	assert.True(t, first != created)
+	assert.Equal(t, 1, buckets.len())
+}
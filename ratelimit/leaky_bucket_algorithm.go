@@ -5,11 +5,11 @@ import (
 	"time"
 )
 
-//LeakyBucket ...
+//LeakyBucket a request is put into the bucket if it is not full, and one request leaks out every interval.
+//The bucket leaks lazily with GCRA when a request is put.
 type LeakyBucket struct {
+	gcra     *GCRA
 	capacity int
-	ch       chan struct{}
-	stop     chan bool
 	interval time.Duration
 }
 
@@ -21,41 +21,27 @@ func NewLeakyBucket(capacity int, interval time.Duration) (*LeakyBucket, error)
 
 	bucket := &LeakyBucket{
 		capacity: capacity,
-		ch:       make(chan struct{}, capacity),
-		stop:     make(chan bool, 1),
 		interval: interval,
+		gcra:     NewGCRA(interval, capacity),
 	}
-	go bucket.run()
 	return bucket, nil
 }
 
-func (b *LeakyBucket) run() {
-	t := time.NewTicker(b.interval)
-	defer t.Stop()
-	for {
-		select {
-		case <-b.stop:
-			return
-		case <-t.C:
-			select {
-			case <-b.ch:
-			default:
-			}
-		}
-	}
-}
-
 //Put ...
 func (b *LeakyBucket) Put() bool {
-	select {
-	case b.ch <- struct{}{}:
-		return true
-	default:
-		return false
-	}
+	return b.gcra.Allow()
+}
+
+//Reserve puts a request which leaks out after the delay, ok is false if the delay is longer than maxWait.
+func (b *LeakyBucket) Reserve(maxWait time.Duration) (delay time.Duration, ok bool) {
+	return b.gcra.Reserve(maxWait)
+}
+
+//Cancel takes back a request put by Reserve.
+func (b *LeakyBucket) Cancel() {
+	b.gcra.Cancel()
 }
 
-//Stop ...
+//Stop the bucket has no goroutine to stop, it is kept for compatibility.
 func (b *LeakyBucket) Stop() {
-	b.stop <- true
 }
@@ -2,20 +2,18 @@ package ratelimit
 
 import (
 	"net/url"
-	"sync"
+	"time"
 
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/server"
 )
 
 var (
-	leakyBucketLock sync.RWMutex
-	leakyBuckets    map[string]*LeakyBucket
+	leakyBuckets *bucketCache
 )
 
 func init() {
-	leakyBuckets = make(map[string]*LeakyBucket)
-	leakyBucketLock = sync.RWMutex{}
+	leakyBuckets = newBucketCache()
 }
 
 //LeakyBucketRateLimit ...
@@ -28,12 +26,37 @@ func NewLeakyBucketRateLimit() *LeakyBucketRateLimit {
 
 //Allow ...
 func (l *LeakyBucketRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
-	if uri == nil || requestConfig == nil {
+	bucket := l.getBucket(uri, serverStats, requestConfig)
+	if bucket == nil {
 		return true
 	}
 
+	return bucket.Put()
+}
+
+//Reserve ...
+func (l *LeakyBucketRateLimit) Reserve(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig,
+	maxWait time.Duration) (time.Duration, func(), bool) {
+	if uri != nil && len(uri.Host) == 0 && serverStats == nil {
+		//every server has its own bucket
+		return 0, nil, true
+	}
+	bucket := l.getBucket(uri, serverStats, requestConfig)
+	if bucket == nil {
+		return 0, func() {}, true
+	}
+
+	delay, ok := bucket.Reserve(maxWait)
+	return delay, bucket.Cancel, ok
+}
+
+func (l *LeakyBucketRateLimit) getBucket(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) *LeakyBucket {
+	if uri == nil || requestConfig == nil {
+		return nil
+	}
+
 	if !requestConfig.GetPropertyAsBool(config.LeakyBucketRateLimitSwitch, config.DefaultLeakyBucketRateLimitSwitch) {
-		return true
+		return nil
 	}
 
 	var key string
@@ -43,33 +66,18 @@ func (l *LeakyBucketRateLimit) Allow(uri *url.URL, serverStats *server.Stats, re
 		key = uri.Host + uri.Path
 	}
 
-	bucket := getLeakyBucket(key, requestConfig)
-	if bucket == nil {
-		return true
-	}
-
-	return bucket.Put()
+	return getLeakyBucket(key, requestConfig)
 }
 
 func getLeakyBucket(key string, requestConfig config.ClientConfig) *LeakyBucket {
-	bucket, ok := leakyBuckets[key]
-	if ok {
-		return bucket
-	}
-	leakyBucketLock.Lock()
-	bucket, ok = leakyBuckets[key]
-	if ok {
-		defer leakyBucketLock.Unlock()
-		return bucket
-	}
-	bucket, err := NewLeakyBucket(
-		requestConfig.GetPropertyAsInteger(config.LeakyBucketCapacity, config.DefaultLeakyBucketCapacity),
-		requestConfig.GetPropertyAsDuration(config.LeakyBucketInterval, config.DefaultLeakyBucketInterval),
-	)
+	bucket, err := leakyBuckets.getOrCreate(key, func() (interface{}, error) {
+		return NewLeakyBucket(
+			requestConfig.GetPropertyAsInteger(config.LeakyBucketCapacity, config.DefaultLeakyBucketCapacity),
+			requestConfig.GetPropertyAsDuration(config.LeakyBucketInterval, config.DefaultLeakyBucketInterval),
+		)
+	})
 	if err != nil {
 		return nil
 	}
-	leakyBuckets[key] = bucket
-	leakyBucketLock.Unlock()
-	return bucket
+	return bucket.(*LeakyBucket)
 }
@@ -0,0 +1,272 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/config"
+)
+
+//QuotaLimiter limits the number of requests in a window, e.g. at most 10,000 requests per hour.
+type QuotaLimiter interface {
+
+	//TakeAt takes a request of the quota, ok is false if the quota is used up. The token identifies the request
+	//taken to Cancel.
+	TakeAt(now time.Time) (token int64, ok bool)
+
+	//Cancel gives back the request taken with the token, nothing if it has left the window.
+	Cancel(token int64)
+
+	//GetRemainingAt returns the number of requests left in the quota, and the time when it grows again.
+	GetRemainingAt(now time.Time) (remaining int, resetAt time.Time)
+
+	//GetLimit ...
+	GetLimit() int
+}
+
+//NewQuotaLimiter the sliding windows use window, and the calendar window uses period in the time zone.
+func NewQuotaLimiter(algorithm string, limit int, window time.Duration, period string, location *time.Location) QuotaLimiter {
+	switch algorithm {
+	case config.SlidingWindowLogQuotaAlgorithm:
+		return NewSlidingWindowLog(limit, window)
+	case config.CalendarWindowQuotaAlgorithm:
+		return NewCalendarWindow(limit, period, location)
+	}
+	return NewSlidingWindowCounter(limit, window)
+}
+
+//SlidingWindowLog keeps the time of every request in the window, it is exact but takes memory in proportion
+//to the limit.
+type SlidingWindowLog struct {
+	lock   sync.Mutex
+	limit  int
+	window int64
+	log    []int64
+}
+
+//NewSlidingWindowLog ...
+func NewSlidingWindowLog(limit int, window time.Duration) *SlidingWindowLog {
+	if window <= 0 {
+		window = time.Second
+	}
+	return &SlidingWindowLog{
+		limit:  limit,
+		window: int64(window),
+		log:    make([]int64, 0),
+	}
+}
+
+//TakeAt the token is the time of the request.
+func (l *SlidingWindowLog) TakeAt(now time.Time) (int64, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	token := now.UnixNano()
+	l.evict(token)
+	if len(l.log) >= l.limit {
+		return 0, false
+	}
+	//the log is kept in order if a request comes with an earlier time than the last one
+	i := len(l.log)
+	for i > 0 && l.log[i-1] > token {
+		i--
+	}
+	l.log = append(l.log, 0)
+	copy(l.log[i+1:], l.log[i:])
+	l.log[i] = token
+	return token, true
+}
+
+//Cancel removes an entry of the time of the token, the requests at the same time are not told apart.
+func (l *SlidingWindowLog) Cancel(token int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for i := len(l.log) - 1; i >= 0 && l.log[i] >= token; i-- {
+		if l.log[i] == token {
+			l.log = append(l.log[:i], l.log[i+1:]...)
+			return
+		}
+	}
+}
+
+//GetRemainingAt ...
+func (l *SlidingWindowLog) GetRemainingAt(now time.Time) (int, time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.evict(now.UnixNano())
+	if len(l.log) == 0 {
+		return l.limit, now
+	}
+	return int(math.Max(0, float64(l.limit-len(l.log)))), time.Unix(0, l.log[0]+l.window)
+}
+
+//GetLimit ...
+func (l *SlidingWindowLog) GetLimit() int {
+	return l.limit
+}
+
+func (l *SlidingWindowLog) evict(now int64) {
+	i := 0
+	for i < len(l.log) && l.log[i] <= now-l.window {
+		i++
+	}
+	//the memory of the evicted ones is reclaimed when the log grows
+	l.log = l.log[i:]
+}
+
+//SlidingWindowCounter estimates the requests in the sliding window from the counts of the current and the
+//previous fixed windows, the previous one is weighted by its part still in the sliding window.
+type SlidingWindowCounter struct {
+	lock     sync.Mutex
+	limit    int
+	window   int64
+	start    int64
+	current  int
+	previous int
+}
+
+//NewSlidingWindowCounter ...
+func NewSlidingWindowCounter(limit int, window time.Duration) *SlidingWindowCounter {
+	if window <= 0 {
+		window = time.Second
+	}
+	return &SlidingWindowCounter{
+		limit:  limit,
+		window: int64(window),
+	}
+}
+
+//TakeAt the token is the start of the fixed window of the request.
+func (c *SlidingWindowCounter) TakeAt(now time.Time) (int64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.estimate(now.UnixNano())+1 > float64(c.limit) {
+		return 0, false
+	}
+	c.current++
+	return c.start, true
+}
+
+//Cancel gives back the request to the count of its fixed window, the current or the previous one.
+func (c *SlidingWindowCounter) Cancel(token int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	switch {
+	case token == c.start && c.current > 0:
+		c.current--
+	case token == c.start-c.window && c.previous > 0:
+		c.previous--
+	}
+}
+
+//GetRemainingAt ...
+func (c *SlidingWindowCounter) GetRemainingAt(now time.Time) (int, time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	estimate := c.estimate(now.UnixNano())
+	return int(math.Max(0, math.Floor(float64(c.limit)-estimate))), time.Unix(0, c.start+c.window)
+}
+
+//GetLimit ...
+func (c *SlidingWindowCounter) GetLimit() int {
+	return c.limit
+}
+
+func (c *SlidingWindowCounter) estimate(now int64) float64 {
+	start := now - now%c.window
+	if start != c.start {
+		if start == c.start+c.window {
+			c.previous = c.current
+		} else {
+			c.previous = 0
+		}
+		c.current = 0
+		c.start = start
+	}
+	weight := float64(c.window-(now-start)) / float64(c.window)
+	return float64(c.previous)*weight + float64(c.current)
+}
+
+//CalendarWindow a fixed window aligned to the calendar in a time zone, e.g. a daily quota which resets at midnight.
+//The weeks start on Monday.
+type CalendarWindow struct {
+	lock     sync.Mutex
+	limit    int
+	period   string
+	location *time.Location
+	count    int
+	end      time.Time
+}
+
+//NewCalendarWindow period is hour|day|week|month, day by default.
+func NewCalendarWindow(limit int, period string, location *time.Location) *CalendarWindow {
+	if location == nil {
+		location = time.Local
+	}
+	switch period {
+	case config.HourCalendarPeriod, config.DayCalendarPeriod, config.WeekCalendarPeriod, config.MonthCalendarPeriod:
+	default:
+		period = config.DayCalendarPeriod
+	}
+	return &CalendarWindow{
+		limit:    limit,
+		period:   period,
+		location: location,
+	}
+}
+
+//TakeAt the token is the end of the window of the request.
+func (w *CalendarWindow) TakeAt(now time.Time) (int64, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.roll(now)
+	if w.count >= w.limit {
+		return 0, false
+	}
+	w.count++
+	return w.end.UnixNano(), true
+}
+
+//Cancel gives back the request only if the window has not reset since.
+func (w *CalendarWindow) Cancel(token int64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if token == w.end.UnixNano() && w.count > 0 {
+		w.count--
+	}
+}
+
+//GetRemainingAt ...
+func (w *CalendarWindow) GetRemainingAt(now time.Time) (int, time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.roll(now)
+	return int(math.Max(0, float64(w.limit-w.count))), w.end
+}
+
+//GetLimit ...
+func (w *CalendarWindow) GetLimit() int {
+	return w.limit
+}
+
+func (w *CalendarWindow) roll(now time.Time) {
+	if now.Before(w.end) {
+		return
+	}
+	w.count = 0
+	w.end = w.nextStart(now)
+}
+
+func (w *CalendarWindow) nextStart(now time.Time) time.Time {
+	t := now.In(w.location)
+	year, month, day := t.Date()
+	switch w.period {
+	case config.HourCalendarPeriod:
+		return time.Date(year, month, day, t.Hour()+1, 0, 0, 0, w.location)
+	case config.WeekCalendarPeriod:
+		return time.Date(year, month, day+7-(int(t.Weekday())+6)%7, 0, 0, 0, 0, w.location)
+	case config.MonthCalendarPeriod:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, w.location)
+	}
+	return time.Date(year, month, day+1, 0, 0, 0, 0, w.location)
+}
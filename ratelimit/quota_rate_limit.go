@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/metric"
+	"github.com/nienie/marathon/server"
+)
+
+var (
+	quotaLimiterLock sync.RWMutex
+	quotaLimiters    map[string]*namedQuotaLimiter
+)
+
+func init() {
+	quotaLimiters = make(map[string]*namedQuotaLimiter)
+	quotaLimiterLock = sync.RWMutex{}
+}
+
+type namedQuotaLimiter struct {
+	QuotaLimiter
+	name string
+}
+
+//QuotaRateLimit limits the requests of the client or of every server with a quota, e.g. at most 10,000 requests
+//per hour, or 100,000 per day which resets at midnight. The remaining quota is reported by metric.QuotaCollector.
+type QuotaRateLimit struct{}
+
+//NewQuotaRateLimit ...
+func NewQuotaRateLimit() *QuotaRateLimit {
+	return &QuotaRateLimit{}
+}
+
+//Allow ...
+func (l *QuotaRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	_, ok := l.Take(uri, serverStats, requestConfig)
+	return ok
+}
+
+//Take cancel gives back the quota of the request rejected by a later rate limit.
+func (l *QuotaRateLimit) Take(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) (func(), bool) {
+	limiter := getQuotaLimiter(serverStats, requestConfig)
+	if limiter == nil {
+		return nil, true
+	}
+	now := time.Now()
+	token, ok := limiter.TakeAt(now)
+	remaining, resetAt := limiter.GetRemainingAt(now)
+	metric.Quota(limiter.name, remaining, resetAt)
+	if !ok {
+		return nil, false
+	}
+	return func() {
+		limiter.Cancel(token)
+	}, true
+}
+
+//GetQuotaLimiter returns the limiter of the client, or of the server if QuotaScope is server, nil if it has not
+//been created.
+func GetQuotaLimiter(clientName string, svr *server.Server) QuotaLimiter {
+	key := clientName
+	if svr != nil {
+		key += "@" + svr.GetHostPort()
+	}
+	quotaLimiterLock.RLock()
+	defer quotaLimiterLock.RUnlock()
+	if limiter, ok := quotaLimiters[key]; ok {
+		return limiter.QuotaLimiter
+	}
+	return nil
+}
+
+//GetRemainingQuota returns the number of requests left in the quota and the time when it grows again, ok is
+//false if the quota has not been created.
+func GetRemainingQuota(clientName string, svr *server.Server) (remaining int, resetAt time.Time, ok bool) {
+	limiter := GetQuotaLimiter(clientName, svr)
+	if limiter == nil {
+		return 0, time.Time{}, false
+	}
+	remaining, resetAt = limiter.GetRemainingAt(time.Now())
+	return remaining, resetAt, true
+}
+
+func getQuotaLimiter(serverStats *server.Stats, requestConfig config.ClientConfig) *namedQuotaLimiter {
+	if requestConfig == nil ||
+		!requestConfig.GetPropertyAsBool(config.QuotaRateLimitSwitch, config.DefaultQuotaRateLimitSwitch) {
+		return nil
+	}
+
+	key := requestConfig.GetClientName()
+	if requestConfig.GetPropertyAsString(config.QuotaScope, config.DefaultQuotaScope) == config.ServerLimitScope {
+		if serverStats == nil || serverStats.Server == nil {
+			return nil
+		}
+		key += "@" + serverStats.Server.GetHostPort()
+	}
+
+	quotaLimiterLock.RLock()
+	limiter, ok := quotaLimiters[key]
+	quotaLimiterLock.RUnlock()
+	if ok {
+		return limiter
+	}
+	quotaLimiterLock.Lock()
+	defer quotaLimiterLock.Unlock()
+	if limiter, ok = quotaLimiters[key]; ok {
+		return limiter
+	}
+	timeZone := requestConfig.GetPropertyAsString(config.QuotaTimeZone, config.DefaultQuotaTimeZone)
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		logger.Warnf(nil, "err_msg=invalid QuotaTimeZone||client=%s||time_zone=%s||err=%v",
+			requestConfig.GetClientName(), timeZone, err)
+		location = time.Local
+	}
+	limiter = &namedQuotaLimiter{
+		QuotaLimiter: NewQuotaLimiter(
+			requestConfig.GetPropertyAsString(config.QuotaAlgorithm, config.DefaultQuotaAlgorithm),
+			requestConfig.GetPropertyAsInteger(config.QuotaLimit, config.DefaultQuotaLimit),
+			requestConfig.GetPropertyAsDuration(config.QuotaWindow, config.DefaultQuotaWindow),
+			requestConfig.GetPropertyAsString(config.QuotaCalendarPeriod, config.DefaultQuotaCalendarPeriod),
+			location,
+		),
+		name: key,
+	}
+	quotaLimiters[key] = limiter
+	return limiter
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func takeQuota(limiter QuotaLimiter, now time.Time) bool {
+	_, ok := limiter.TakeAt(now)
+	return ok
+}
+
+//TestSlidingWindowLog ...
+func TestSlidingWindowLog(t *testing.T) {
+	limiter := NewSlidingWindowLog(2, time.Minute)
+	now := time.Unix(1000, 0)
+	assert.True(t, takeQuota(limiter, now))
+	assert.True(t, takeQuota(limiter, now.Add(30*time.Second)))
+	assert.False(t, takeQuota(limiter, now.Add(59*time.Second)))
+	remaining, resetAt := limiter.GetRemainingAt(now.Add(59 * time.Second))
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, now.Add(time.Minute), resetAt)
+
+	//the first request leaves the window
+	token, ok := limiter.TakeAt(now.Add(time.Minute))
+	assert.True(t, ok)
+	assert.False(t, takeQuota(limiter, now.Add(time.Minute)))
+	limiter.Cancel(token)
+	assert.True(t, takeQuota(limiter, now.Add(time.Minute)))
+
+	//only the request of the token is given back, even if a later one is taken in between
+	limiter = NewSlidingWindowLog(2, time.Minute)
+	token, _ = limiter.TakeAt(now)
+	assert.True(t, takeQuota(limiter, now.Add(time.Second)))
+	limiter.Cancel(token)
+	remaining, resetAt = limiter.GetRemainingAt(now.Add(time.Second))
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, now.Add(time.Second+time.Minute), resetAt)
+	//the request has left the window
+	limiter.Cancel(token)
+	remaining, _ = limiter.GetRemainingAt(now.Add(time.Second))
+	assert.Equal(t, 1, remaining)
+}
+
+//TestSlidingWindowCounter ...
+func TestSlidingWindowCounter(t *testing.T) {
+	limiter := NewSlidingWindowCounter(10, time.Minute)
+	now := time.Unix(6000, 0)
+	for i := 0; i < 10; i++ {
+		assert.True(t, takeQuota(limiter, now))
+	}
+	assert.False(t, takeQuota(limiter, now.Add(59*time.Second)))
+
+	//half of the previous window is still in the sliding window
+	later := now.Add(90 * time.Second)
+	remaining, resetAt := limiter.GetRemainingAt(later)
+	assert.Equal(t, 5, remaining)
+	assert.Equal(t, now.Add(2*time.Minute), resetAt)
+	for i := 0; i < 5; i++ {
+		assert.True(t, takeQuota(limiter, later))
+	}
+	assert.False(t, takeQuota(limiter, later))
+
+	//a request of the previous window is given back to the previous count, not to the current one
+	limiter = NewSlidingWindowCounter(10, time.Minute)
+	token, ok := limiter.TakeAt(now)
+	assert.True(t, ok)
+	for i := 0; i < 9; i++ {
+		assert.True(t, takeQuota(limiter, now))
+	}
+	for i := 0; i < 5; i++ {
+		assert.True(t, takeQuota(limiter, later))
+	}
+	limiter.Cancel(token)
+	assert.Equal(t, 5, limiter.current)
+	assert.Equal(t, 9, limiter.previous)
+	//the window of the token is too old
+	limiter.Cancel(token - int64(time.Minute))
+	assert.Equal(t, 5, limiter.current)
+	assert.Equal(t, 9, limiter.previous)
+
+	//the windows are too old
+	remaining, _ = limiter.GetRemainingAt(now.Add(10 * time.Minute))
+	assert.Equal(t, 10, remaining)
+}
+
+//TestCalendarWindow ...
+func TestCalendarWindow(t *testing.T) {
+	location := time.FixedZone("UTC+8", 8*3600)
+	limiter := NewCalendarWindow(2, config.DayCalendarPeriod, location)
+	now := time.Date(2018, 5, 1, 23, 0, 0, 0, location)
+	assert.True(t, takeQuota(limiter, now))
+	assert.True(t, takeQuota(limiter, now))
+	assert.False(t, takeQuota(limiter, now.Add(59*time.Minute)))
+	remaining, resetAt := limiter.GetRemainingAt(now)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, time.Date(2018, 5, 2, 0, 0, 0, 0, location), resetAt)
+	//resets at midnight, the request of the previous day is not given back to the new day
+	token, _ := NewCalendarWindow(2, config.DayCalendarPeriod, location).TakeAt(now)
+	assert.True(t, takeQuota(limiter, now.Add(time.Hour)))
+	limiter.Cancel(token)
+	remaining, _ = limiter.GetRemainingAt(now.Add(time.Hour))
+	assert.Equal(t, 1, remaining)
+
+	//2018-05-02 is a Wednesday
+	week := NewCalendarWindow(1, config.WeekCalendarPeriod, location)
+	_, resetAt = week.GetRemainingAt(now.Add(time.Hour))
+	assert.Equal(t, time.Date(2018, 5, 7, 0, 0, 0, 0, location), resetAt)
+	month := NewCalendarWindow(1, config.MonthCalendarPeriod, location)
+	_, resetAt = month.GetRemainingAt(now)
+	assert.Equal(t, time.Date(2018, 6, 1, 0, 0, 0, 0, location), resetAt)
+	hour := NewCalendarWindow(1, config.HourCalendarPeriod, location)
+	_, resetAt = hour.GetRemainingAt(now)
+	assert.Equal(t, time.Date(2018, 5, 2, 0, 0, 0, 0, location), resetAt)
+}
+
+//TestQuotaRateLimit ...
+func TestQuotaRateLimit(t *testing.T) {
+	quotaLimiterLock.Lock()
+	quotaLimiters = make(map[string]*namedQuotaLimiter)
+	quotaLimiterLock.Unlock()
+
+	requestConfig := config.NewDefaultClientConfig("quota", nil)
+	requestConfig.SetProperty(config.QuotaRateLimitSwitch, true)
+	requestConfig.SetProperty(config.QuotaAlgorithm, config.CalendarWindowQuotaAlgorithm)
+	requestConfig.SetProperty(config.QuotaLimit, 3)
+	requestConfig.SetProperty(config.QuotaCalendarPeriod, config.MonthCalendarPeriod)
+	requestConfig.SetProperty(config.QuotaTimeZone, "UTC")
+	uri, _ := url.Parse("http://127.0.0.1:8080/quota")
+
+	_, _, ok := GetRemainingQuota("quota", nil)
+	assert.False(t, ok)
+	for i := 0; i < 3; i++ {
+		assert.True(t, Allow(uri, nil, requestConfig))
+	}
+	assert.False(t, Allow(uri, nil, requestConfig))
+	remaining, resetAt, ok := GetRemainingQuota("quota", nil)
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, 1, resetAt.Day())
+	assert.Equal(t, time.UTC, resetAt.Location())
+
+	//the quota of the request rejected by a later rate limit is given back
+	RegisterRateLimit(&rejectingRateLimit{clientName: "rejectedquota"})
+	rejectedConfig := config.NewDefaultClientConfig("rejectedquota", nil)
+	rejectedConfig.SetProperty(config.QuotaRateLimitSwitch, true)
+	assert.False(t, Allow(uri, nil, rejectedConfig))
+	remaining, _, _ = GetRemainingQuota("rejectedquota", nil)
+	assert.Equal(t, config.DefaultQuotaLimit, remaining)
+}
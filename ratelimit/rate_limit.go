@@ -2,7 +2,9 @@ package ratelimit
 
 import (
 	"net/url"
+	"time"
 
+	"github.com/nienie/marathon/client"
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/server"
 )
@@ -13,3 +15,31 @@ type RateLimit interface {
 	//Allow ...
 	Allow(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool
 }
+
+//Waiter is an optional interface of RateLimit, it reserves a permit for the request in the waiting mode instead of
+//rejecting it, and the request is sent after the delay.
+type Waiter interface {
+
+	//Reserve ok is false if no permit is available within maxWait, cancel gives the permit back if the request
+	//does not wait for it. serverStats is nil before the server is chosen, cancel is nil if the permit depends on
+	//the server, then the permit is checked with Allow when the server is chosen.
+	Reserve(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig, maxWait time.Duration) (
+		delay time.Duration, cancel func(), ok bool)
+}
+
+//Taker is an optional interface of RateLimit, it is used instead of Allow. The permit taken is given back by cancel
+//if a later RateLimit rejects the request, so a rejected request never gives back the permit of another request.
+type Taker interface {
+
+	//Take cancel may be nil if there is nothing to give back.
+	Take(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) (cancel func(), ok bool)
+}
+
+//Feedback is an optional interface of RateLimit, it is told the result of every request it allows, e.g. to adjust
+//the limit from the latency. A request allowed but rejected by a later RateLimit completes with errors.ClientThrottled.
+type Feedback interface {
+
+	//OnComplete ...
+	OnComplete(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig, response client.Response,
+		latency time.Duration, err error)
+}
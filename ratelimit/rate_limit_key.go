@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+)
+
+//RouteTemplate matches the paths with a template like /users/{id}/orders, a segment in braces or "*" matches
+//any segment, so that the requests to the paths with ids share one rate limit.
+type RouteTemplate struct {
+	template string
+	segments []string
+}
+
+//NewRouteTemplate ...
+func NewRouteTemplate(template string) *RouteTemplate {
+	return &RouteTemplate{
+		template: template,
+		segments: strings.Split(strings.Trim(template, "/"), "/"),
+	}
+}
+
+//GetTemplate ...
+func (r *RouteTemplate) GetTemplate() string {
+	return r.template
+}
+
+//Match ...
+func (r *RouteTemplate) Match(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, segment := range r.segments {
+		if segment == "*" || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) {
+			if len(segments[i]) == 0 {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//KeyExtractor builds the key of a request from the dimensions of a rate limit rule, see config.RateLimitKey.
+type KeyExtractor struct {
+	dimensions []string
+	routes     []*RouteTemplate
+}
+
+//NewKeyExtractor key is the dimensions joined with "+", e.g. "header:X-Tenant+route", routes are the route templates
+//separated by ",".
+func NewKeyExtractor(key string, routes string) (*KeyExtractor, error) {
+	e := &KeyExtractor{}
+	for _, dimension := range strings.Split(key, "+") {
+		dimension = strings.TrimSpace(dimension)
+		switch {
+		case dimension == config.ClientLimitKey, dimension == config.ServerLimitKey, dimension == config.RouteLimitKey:
+		case strings.HasPrefix(dimension, config.HeaderLimitKeyPrefix) && len(dimension) > len(config.HeaderLimitKeyPrefix):
+		default:
+			return nil, fmt.Errorf("invalid rate limit key dimension %q", dimension)
+		}
+		e.dimensions = append(e.dimensions, dimension)
+	}
+	for _, route := range strings.Split(routes, ",") {
+		if route = strings.TrimSpace(route); len(route) > 0 {
+			e.routes = append(e.routes, NewRouteTemplate(route))
+		}
+	}
+	return e, nil
+}
+
+//Extract ok is false if the request is not limited by the rule, i.e. the header is missing or the path matches
+//none of the route templates. The requests of different clients never share a key.
+func (e *KeyExtractor) Extract(request client.Request, serverStats *server.Stats, requestConfig config.ClientConfig) (
+	key string, ok bool) {
+	parts := make([]string, 0, len(e.dimensions)+1)
+	if requestConfig != nil {
+		parts = append(parts, requestConfig.GetClientName())
+	}
+	uri := request.GetURI()
+	for _, dimension := range e.dimensions {
+		switch {
+		case dimension == config.ClientLimitKey:
+		case dimension == config.ServerLimitKey:
+			if serverStats != nil && serverStats.Server != nil {
+				parts = append(parts, serverStats.Server.GetHostPort())
+			} else if uri != nil {
+				parts = append(parts, uri.Host)
+			}
+		case dimension == config.RouteLimitKey:
+			route, ok := e.matchRoute(uri)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, route)
+		default:
+			value := getHeader(request.GetHeaders(), dimension[len(config.HeaderLimitKeyPrefix):])
+			if len(value) == 0 {
+				return "", false
+			}
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "|"), true
+}
+
+func (e *KeyExtractor) hasDimension(dimension string) bool {
+	for _, d := range e.dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *KeyExtractor) matchRoute(uri *url.URL) (string, bool) {
+	if uri == nil {
+		return "", false
+	}
+	if len(e.routes) == 0 {
+		return uri.Path, true
+	}
+	for _, route := range e.routes {
+		if route.Match(uri.Path) {
+			return route.GetTemplate(), true
+		}
+	}
+	return "", false
+}
+
+func getHeader(headers map[string][]string, name string) string {
+	if values := http.Header(headers)[http.CanonicalHeaderKey(name)]; len(values) > 0 {
+		return values[0]
+	}
+	for key, values := range headers {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -1,9 +1,13 @@
 package ratelimit
 
 import (
+	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/nienie/marathon/client"
 	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
 	"github.com/nienie/marathon/server"
 )
 
@@ -16,19 +20,74 @@ func init() {
 	rateLimitRegister = append(rateLimitRegister, NewConcurrencyRateLimit())
 	rateLimitRegister = append(rateLimitRegister, NewTokenBucketRateLimit())
 	rateLimitRegister = append(rateLimitRegister, NewLeakyBucketRateLimit())
+	rateLimitRegister = append(rateLimitRegister, NewDistributedRateLimit())
+	rateLimitRegister = append(rateLimitRegister, NewQuotaRateLimit())
+	rateLimitRegister = append(rateLimitRegister, NewAdaptiveConcurrencyRateLimit())
+	rateLimitRegister = append(rateLimitRegister, NewSREThrottlingRateLimit())
 }
 
 //Allow ...
 func Allow(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
-	for _, rateLimit := range rateLimitRegister {
-		if rateLimit.Allow(url, serverStats, requestConfig) == false {
+	return allow(rateLimitRegister, url, serverStats, requestConfig)
+}
+
+func allow(rateLimits []RateLimit, url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	var cancels []func()
+	for i, rateLimit := range rateLimits {
+		ok := true
+		if taker, isTaker := rateLimit.(Taker); isTaker {
+			var cancel func()
+			if cancel, ok = taker.Take(url, serverStats, requestConfig); cancel != nil {
+				cancels = append(cancels, cancel)
+			}
+		} else {
+			ok = rateLimit.Allow(url, serverStats, requestConfig)
+		}
+		if ok == false {
+			for _, cancel := range cancels {
+				cancel()
+			}
+			//the request never completes for the ones which allowed it
+			err := errors.NewClientError(errors.ClientThrottled, nil)
+			for _, allowed := range rateLimits[:i] {
+				if feedback, ok := allowed.(Feedback); ok {
+					feedback.OnComplete(url, serverStats, requestConfig, nil, 0, err)
+				}
+			}
 			return false
 		}
 	}
 	return true
 }
 
+//OnComplete tells the result of a request allowed by Allow to the RateLimits which implement Feedback.
+func OnComplete(url *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig, response client.Response,
+	latency time.Duration, err error) {
+	for _, rateLimit := range rateLimitRegister {
+		if feedback, ok := rateLimit.(Feedback); ok {
+			feedback.OnComplete(url, serverStats, requestConfig, response, latency, err)
+		}
+	}
+}
+
 //RegisterRateLimit ...
 func RegisterRateLimit(r RateLimit) {
 	rateLimitRegister = append(rateLimitRegister, r)
 }
+
+//IsOverloaded whether the upstream rejects the request or times out because of the load,
+//e.g. 429, 502/503/504 or errors.ServerThrottled.
+func IsOverloaded(response client.Response, err error) bool {
+	if err == nil {
+		return response != nil && response.GetStatusCode() == http.StatusTooManyRequests
+	}
+	clientErr, ok := err.(errors.ClientError)
+	if !ok {
+		return false
+	}
+	switch clientErr.GetErrType() {
+	case errors.ServerThrottled, errors.SocketTimeoutException, errors.ReadTimeoutException:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/server"
+)
+
+var (
+	ruleLock    sync.RWMutex
+	rules       map[string]*Rule
+	ruleBuckets *bucketCache
+)
+
+func init() {
+	rules = make(map[string]*Rule)
+	ruleLock = sync.RWMutex{}
+	ruleBuckets = newBucketCache()
+}
+
+//reservable a bucket of a rate limit rule.
+type reservable interface {
+	Reserve(maxWait time.Duration) (time.Duration, bool)
+	Cancel()
+}
+
+//Rule a rate limit rule configured by config.RateLimitRules, the requests with the same key share a bucket of the
+//algorithm of the rule. All the rules of the client apply to a request.
+type Rule struct {
+	id        string
+	name      string
+	extractor *KeyExtractor
+	algorithm string
+	capacity  int
+	interval  time.Duration
+	count     int
+}
+
+//NewRule ...
+func NewRule(name string, extractor *KeyExtractor, algorithm string, capacity int, interval time.Duration, count int) (
+	*Rule, error) {
+	if algorithm != config.TokenBucketRateLimitAlgorithm && algorithm != config.LeakyBucketRateLimitAlgorithm {
+		return nil, fmt.Errorf("invalid rate limit algorithm %q", algorithm)
+	}
+	if capacity <= 0 || interval <= 0 || count <= 0 {
+		return nil, fmt.Errorf("invalid parameters")
+	}
+	return &Rule{
+		id:        fmt.Sprintf("%s|%s|%d|%s|%d", name, algorithm, capacity, interval, count),
+		name:      name,
+		extractor: extractor,
+		algorithm: algorithm,
+		capacity:  capacity,
+		interval:  interval,
+		count:     count,
+	}, nil
+}
+
+//GetName ...
+func (r *Rule) GetName() string {
+	return r.name
+}
+
+//Reserve takes a permit from the bucket of the request, see Waiter. The request is allowed without a permit if
+//the rule does not apply to it.
+func (r *Rule) Reserve(request client.Request, serverStats *server.Stats, requestConfig config.ClientConfig,
+	maxWait time.Duration) (time.Duration, func(), bool) {
+	if request == nil {
+		return 0, func() {}, true
+	}
+	if serverStats == nil && r.extractor.hasDimension(config.ServerLimitKey) {
+		return 0, nil, true
+	}
+	key, ok := r.extractor.Extract(request, serverStats, requestConfig)
+	if !ok {
+		return 0, func() {}, true
+	}
+	bucket, err := ruleBuckets.getOrCreate(r.id+"@"+key, r.newBucket)
+	if err != nil {
+		return 0, func() {}, true
+	}
+	delay, ok := bucket.(reservable).Reserve(maxWait)
+	return delay, bucket.(reservable).Cancel, ok
+}
+
+func (r *Rule) newBucket() (interface{}, error) {
+	if r.algorithm == config.LeakyBucketRateLimitAlgorithm {
+		return NewLeakyBucket(r.capacity, r.interval)
+	}
+	return NewTokenBucket(r.capacity, r.interval, r.count)
+}
+
+//GetRules returns the rules of config.RateLimitRules, the invalid ones are ignored.
+func GetRules(requestConfig config.ClientConfig) []*Rule {
+	if requestConfig == nil {
+		return nil
+	}
+	names := requestConfig.GetPropertyAsString(config.RateLimitRules, config.DefaultRateLimitRules)
+	if len(names) == 0 {
+		return nil
+	}
+	result := make([]*Rule, 0)
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); len(name) == 0 {
+			continue
+		}
+		if rule := getRule(name, requestConfig); rule != nil {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func getRule(name string, requestConfig config.ClientConfig) *Rule {
+	prefix := config.RateLimitRulePrefix + name + "."
+	key := requestConfig.GetPropertyAsString(prefix+config.RateLimitRuleKey, config.DefaultRateLimitRuleKey)
+	routes := requestConfig.GetPropertyAsString(prefix+config.RateLimitRuleRoutes, "")
+	algorithm := requestConfig.GetPropertyAsString(prefix+config.RateLimitRuleAlgorithm, config.DefaultRateLimitRuleAlgorithm)
+	capacity := requestConfig.GetPropertyAsInteger(prefix+config.RateLimitRuleCapacity, config.DefaultTokenBucketCapacity)
+	interval := requestConfig.GetPropertyAsDuration(prefix+config.RateLimitRuleInterval, config.DefaultTokenBucketFillInterval)
+	count := requestConfig.GetPropertyAsInteger(prefix+config.RateLimitRuleCount, config.DefaultTokenBucketFillCount)
+
+	//the rules are parsed once, and a rule changed is a new rule
+	id := fmt.Sprintf("%s|%s|%s|%s|%s|%d|%s|%d", requestConfig.GetClientName(), name, key, routes, algorithm,
+		capacity, interval, count)
+	ruleLock.RLock()
+	rule, ok := rules[id]
+	ruleLock.RUnlock()
+	if ok {
+		return rule
+	}
+	ruleLock.Lock()
+	defer ruleLock.Unlock()
+	if rule, ok = rules[id]; ok {
+		return rule
+	}
+	extractor, err := NewKeyExtractor(key, routes)
+	if err == nil {
+		rule, err = NewRule(name, extractor, algorithm, capacity, interval, count)
+	}
+	if err != nil {
+		logger.Warnf(nil, "err_msg=invalid rate limit rule||client=%s||rule=%s||err=%v",
+			requestConfig.GetClientName(), name, err)
+	}
+	rules[id] = rule
+	return rule
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	uri     *url.URL
+	headers map[string][]string
+}
+
+func newTestRequest(rawURL string, headers map[string][]string) *testRequest {
+	uri, _ := url.Parse(rawURL)
+	return &testRequest{
+		uri:     uri,
+		headers: headers,
+	}
+}
+
+func (r *testRequest) GetURI() *url.URL {
+	return r.uri
+}
+
+func (r *testRequest) GetLoadBalancerKey() interface{} {
+	return nil
+}
+
+func (r *testRequest) ReplaceURI(uri *url.URL) {
+	r.uri = uri
+}
+
+func (r *testRequest) GetHeaders() map[string][]string {
+	return r.headers
+}
+
+func (r *testRequest) GetBodyContents() []byte {
+	return nil
+}
+
+//TestRouteTemplate ...
+func TestRouteTemplate(t *testing.T) {
+	route := NewRouteTemplate("/users/{id}/orders/*")
+	assert.True(t, route.Match("/users/1/orders/2"))
+	assert.True(t, route.Match("users/1/orders/2/"))
+	assert.False(t, route.Match("/users/1/orders"))
+	assert.False(t, route.Match("/users//orders/2"))
+	assert.False(t, route.Match("/accounts/1/orders/2"))
+}
+
+//TestKeyExtractor ...
+func TestKeyExtractor(t *testing.T) {
+	_, err := NewKeyExtractor("client+cookie", "")
+	assert.NotNil(t, err)
+
+	extractor, err := NewKeyExtractor("header:X-Tenant+route", "/users/{id}")
+	assert.Nil(t, err)
+	requestConfig := config.NewDefaultClientConfig("key", nil)
+	key, ok := extractor.Extract(newTestRequest("/users/1", map[string][]string{"X-Tenant": {"a"}}), nil, requestConfig)
+	assert.True(t, ok)
+	assert.Equal(t, "key|a|/users/{id}", key)
+	key, ok = extractor.Extract(newTestRequest("/users/2", map[string][]string{"x-tenant": {"a"}}), nil, requestConfig)
+	assert.True(t, ok)
+	assert.Equal(t, "key|a|/users/{id}", key)
+	//not limited by the rule
+	_, ok = extractor.Extract(newTestRequest("/users/1", nil), nil, requestConfig)
+	assert.False(t, ok)
+	_, ok = extractor.Extract(newTestRequest("/orders/1", map[string][]string{"X-Tenant": {"a"}}), nil, requestConfig)
+	assert.False(t, ok)
+
+	extractor, _ = NewKeyExtractor("server", "")
+	svr := server.NewServer("http", "127.0.0.1", 8080)
+	serverStats := server.NewDefaultServerStats()
+	serverStats.Initialize(svr)
+	key, _ = extractor.Extract(newTestRequest("/users/1", nil), serverStats, requestConfig)
+	assert.Equal(t, "key|127.0.0.1:8080", key)
+}
+
+//TestRateLimitRules ...
+func TestRateLimitRules(t *testing.T) {
+	ruleBuckets = newBucketCache()
+
+	requestConfig := config.NewDefaultClientConfig("rules", nil)
+	requestConfig.SetProperty(config.RateLimitRules, "tenant, users, invalid")
+	requestConfig.SetProperty("RateLimitRule.tenant.Key", "header:X-Tenant")
+	requestConfig.SetProperty("RateLimitRule.tenant.Capacity", 2)
+	requestConfig.SetProperty("RateLimitRule.tenant.Interval", time.Hour)
+	requestConfig.SetProperty("RateLimitRule.users.Key", "route")
+	requestConfig.SetProperty("RateLimitRule.users.Routes", "/users/{id}")
+	requestConfig.SetProperty("RateLimitRule.users.Algorithm", "LeakyBucket")
+	requestConfig.SetProperty("RateLimitRule.users.Capacity", 3)
+	requestConfig.SetProperty("RateLimitRule.users.Interval", time.Hour)
+	requestConfig.SetProperty("RateLimitRule.invalid.Algorithm", "Unknown")
+	assert.Equal(t, 2, len(GetRules(requestConfig)))
+
+	var reservation *Reservation
+	tenantA := map[string][]string{"X-Tenant": {"a"}}
+	tenantB := map[string][]string{"X-Tenant": {"b"}}
+	assert.Nil(t, reservation.Check(newTestRequest("/users/1", tenantA), nil, requestConfig))
+	assert.Nil(t, reservation.Check(newTestRequest("/users/2", tenantA), nil, requestConfig))
+	//the limit of the tenant a is used up
+	assert.NotNil(t, reservation.Check(newTestRequest("/orders/1", tenantA), nil, requestConfig))
+	//the paths with ids share the limit of the route
+	assert.Nil(t, reservation.Check(newTestRequest("/users/3", tenantB), nil, requestConfig))
+	assert.NotNil(t, reservation.Check(newTestRequest("/users/4", tenantB), nil, requestConfig))
+	//the permit of the tenant b is given back when the route rejects the request
+	assert.Nil(t, reservation.Check(newTestRequest("/orders/1", tenantB), nil, requestConfig))
+	//not limited by any rule
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, reservation.Check(newTestRequest("/orders/1", nil), nil, requestConfig))
+	}
+}
@@ -0,0 +1,230 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/metric"
+	"github.com/nienie/marathon/server"
+)
+
+var (
+	waiterLock sync.RWMutex
+	waiters    map[string]*int64
+)
+
+func init() {
+	waiters = make(map[string]*int64)
+	waiterLock = sync.RWMutex{}
+}
+
+//Reservation the permits reserved by Wait before the server is chosen, the rules and the RateLimits reserved
+//are not checked again by the attempts of the request.
+type Reservation struct {
+	rules      map[*Rule]bool
+	rateLimits map[int]bool
+	cancels    []func()
+	cancelOnce sync.Once
+}
+
+//Wait reserves the permits of the request before the server is chosen if RateLimitWaitSwitch is on, so the request
+//does not hold a connection or count as a slow call while it waits. The rules of config.RateLimitRules and the
+//RateLimits which implement Waiter reserve the permits which do not depend on the server, and the request waits for
+//them, up to RateLimitMaxWait or the deadline of the context. It fails with errors.ClientThrottled if no permit is
+//available in time or too many requests of the client are waiting. The reservation is nil in the rejecting mode.
+func Wait(ctx context.Context, request client.Request, requestConfig config.ClientConfig) (*Reservation, error) {
+	if requestConfig == nil ||
+		!requestConfig.GetPropertyAsBool(config.RateLimitWaitSwitch, config.DefaultRateLimitWaitSwitch) {
+		return nil, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var uri *url.URL
+	if request != nil {
+		uri = request.GetURI()
+	}
+	name := requestConfig.GetClientName()
+	maxWait := requestConfig.GetPropertyAsDuration(config.RateLimitMaxWait, config.DefaultRateLimitMaxWait)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < maxWait {
+		maxWait = time.Until(deadline)
+	}
+	if maxWait < 0 {
+		maxWait = 0
+	}
+
+	var (
+		delay   time.Duration
+		cancels []func()
+	)
+	cancel := func() {
+		for _, c := range cancels {
+			c()
+		}
+	}
+	//reserved is false if the permit depends on the server
+	reserve := func(d time.Duration, c func(), ok bool) (reserved bool, err error) {
+		if !ok {
+			cancel()
+			metric.RateLimitWait(name, 0, true)
+			return false, errors.NewClientError(errors.ClientThrottled,
+				fmt.Errorf("client %s has no rate limit permit within %s", name, maxWait))
+		}
+		if c == nil {
+			return false, nil
+		}
+		cancels = append(cancels, c)
+		if d > delay {
+			delay = d
+		}
+		return true, nil
+	}
+
+	reservation := &Reservation{
+		rules:      make(map[*Rule]bool),
+		rateLimits: make(map[int]bool),
+	}
+	for _, rule := range GetRules(requestConfig) {
+		reserved, err := reserve(rule.Reserve(request, nil, requestConfig, maxWait))
+		if err != nil {
+			return nil, err
+		}
+		reservation.rules[rule] = reserved
+	}
+	for i, rateLimit := range rateLimitRegister {
+		waiter, ok := rateLimit.(Waiter)
+		if !ok {
+			continue
+		}
+		reserved, err := reserve(waiter.Reserve(uri, nil, requestConfig, maxWait))
+		if err != nil {
+			return nil, err
+		}
+		reservation.rateLimits[i] = reserved
+	}
+	if err := wait(ctx, name, delay,
+		requestConfig.GetPropertyAsInteger(config.RateLimitMaxWaiters, config.DefaultRateLimitMaxWaiters)); err != nil {
+		cancel()
+		return nil, err
+	}
+	reservation.cancels = cancels
+	return reservation, nil
+}
+
+//Cancel returns the reserved permits, e.g. when the request is rejected by the circuit breaker or the bulkhead
+//and never sent. It is called at most once, the later calls do nothing.
+func (r *Reservation) Cancel() {
+	if r == nil {
+		return
+	}
+	r.cancelOnce.Do(func() {
+		for _, c := range r.cancels {
+			c()
+		}
+	})
+}
+
+//Check checks an attempt of the request on the chosen server without waiting, with the rules and the registered
+//RateLimits not reserved by Wait. It fails with errors.ClientThrottled if the attempt is rejected.
+func (r *Reservation) Check(request client.Request, serverStats *server.Stats, requestConfig config.ClientConfig) error {
+	var uri *url.URL
+	if request != nil {
+		uri = request.GetURI()
+	}
+	cancels := make([]func(), 0)
+	cancel := func() {
+		for _, c := range cancels {
+			c()
+		}
+	}
+	for _, rule := range GetRules(requestConfig) {
+		if r != nil && r.rules[rule] {
+			continue
+		}
+		_, c, ok := rule.Reserve(request, serverStats, requestConfig, 0)
+		if !ok {
+			cancel()
+			return errors.NewClientError(errors.ClientThrottled, nil)
+		}
+		if c != nil {
+			cancels = append(cancels, c)
+		}
+	}
+	rateLimits := rateLimitRegister
+	if r != nil {
+		rateLimits = make([]RateLimit, 0, len(rateLimitRegister))
+		for i, rateLimit := range rateLimitRegister {
+			if !r.rateLimits[i] {
+				rateLimits = append(rateLimits, rateLimit)
+			}
+		}
+	}
+	if allow(rateLimits, uri, serverStats, requestConfig) == false {
+		cancel()
+		return errors.NewClientError(errors.ClientThrottled, nil)
+	}
+	return nil
+}
+
+//GetWaitingCount returns the number of requests of the client waiting for the rate limits.
+func GetWaitingCount(clientName string) int64 {
+	waiterLock.RLock()
+	defer waiterLock.RUnlock()
+	waiting, ok := waiters[clientName]
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt64(waiting)
+}
+
+func wait(ctx context.Context, name string, delay time.Duration, maxWaiters int) error {
+	if delay <= 0 {
+		metric.RateLimitWait(name, 0, false)
+		return nil
+	}
+
+	waiting := getWaiting(name)
+	defer atomic.AddInt64(waiting, -1)
+	if atomic.AddInt64(waiting, 1) > int64(maxWaiters) {
+		metric.RateLimitWait(name, 0, true)
+		return errors.NewClientError(errors.ClientThrottled,
+			fmt.Errorf("client %s has too many requests waiting for the rate limits, max waiters=%d", name, maxWaiters))
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		metric.RateLimitWait(name, time.Since(start), false)
+		return nil
+	case <-ctx.Done():
+		metric.RateLimitWait(name, time.Since(start), true)
+		return errors.NewClientError(errors.ClientThrottled,
+			fmt.Errorf("client %s gives up waiting for the rate limits, err=%v", name, ctx.Err()))
+	}
+}
+
+func getWaiting(name string) *int64 {
+	waiterLock.RLock()
+	waiting, ok := waiters[name]
+	waiterLock.RUnlock()
+	if ok {
+		return waiting
+	}
+	waiterLock.Lock()
+	defer waiterLock.Unlock()
+	if waiting, ok = waiters[name]; ok {
+		return waiting
+	}
+	waiting = new(int64)
+	waiters[name] = waiting
+	return waiting
+}
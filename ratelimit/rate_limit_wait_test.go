@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/server"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestGCRAReserve ...
+func TestGCRAReserve(t *testing.T) {
+	gcra := NewGCRA(10*time.Millisecond, 2)
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		delay, ok := gcra.ReserveAt(now, 0)
+		assert.True(t, ok)
+		assert.Equal(t, time.Duration(0), delay)
+	}
+	_, ok := gcra.ReserveAt(now, 5*time.Millisecond)
+	assert.False(t, ok)
+	delay, ok := gcra.ReserveAt(now, 10*time.Millisecond)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, delay)
+
+	//the reserved request is given back
+	gcra.Cancel()
+	delay, ok = gcra.ReserveAt(now, 10*time.Millisecond)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, delay)
+}
+
+//TestWait ...
+func TestWait(t *testing.T) {
+	tokenBuckets = newBucketCache()
+
+	requestConfig := config.NewDefaultClientConfig("wait", nil)
+	requestConfig.SetProperty(config.TokenBucketRateLimitSwitch, true)
+	requestConfig.SetProperty(config.TokenBucketCapacity, 1)
+	requestConfig.SetProperty(config.TokenBucketFillInterval, 50*time.Millisecond)
+	requestConfig.SetProperty(config.TokenBucketFillCount, 1)
+	request := newTestRequest("http://127.0.0.1:8080/wait", nil)
+	uri := request.GetURI()
+
+	//rejected at once without the waiting mode
+	reservation, err := Wait(context.Background(), request, requestConfig)
+	assert.Nil(t, err)
+	assert.Nil(t, reservation)
+	assert.Nil(t, reservation.Check(request, nil, requestConfig))
+	err = reservation.Check(request, nil, requestConfig)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ClientThrottled, err.(errors.ClientError).GetErrType())
+
+	requestConfig.SetProperty(config.RateLimitWaitSwitch, true)
+	start := time.Now()
+	reservation, err = Wait(context.Background(), request, requestConfig)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+	//the permit reserved before the server is chosen is not taken again by the attempts
+	assert.Nil(t, reservation.Check(request, nil, requestConfig))
+	assert.Nil(t, reservation.Check(request, nil, requestConfig))
+	//the permit of a request which is never sent is given back once
+	reservation.Cancel()
+	reservation.Cancel()
+	assert.True(t, Allow(uri, nil, requestConfig))
+	assert.False(t, Allow(uri, nil, requestConfig))
+
+	//the deadline of the context is shorter than the delay
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = Wait(ctx, request, requestConfig)
+	assert.NotNil(t, err)
+
+	//too many waiters
+	requestConfig.SetProperty(config.RateLimitMaxWaiters, 0)
+	_, err = Wait(context.Background(), request, requestConfig)
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(0), GetWaitingCount("wait"))
+
+	//the permits of the rejected requests are given back
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, Allow(uri, nil, requestConfig))
+}
+
+//TestWaitServerBuckets ...
+func TestWaitServerBuckets(t *testing.T) {
+	tokenBuckets = newBucketCache()
+
+	requestConfig := config.NewDefaultClientConfig("wait_server", nil)
+	requestConfig.SetProperty(config.TokenBucketRateLimitSwitch, true)
+	requestConfig.SetProperty(config.TokenBucketCapacity, 1)
+	requestConfig.SetProperty(config.TokenBucketFillInterval, time.Hour)
+	requestConfig.SetProperty(config.TokenBucketFillCount, 1)
+	requestConfig.SetProperty(config.RateLimitWaitSwitch, true)
+	request := newTestRequest("/wait", nil)
+	serverStats := server.NewDefaultServerStats()
+	serverStats.Initialize(server.NewServer("http", "127.0.0.1", 8080))
+
+	//the buckets of the servers are not known before the server is chosen, they are checked without waiting
+	for i := 0; i < 2; i++ {
+		reservation, err := Wait(context.Background(), request, requestConfig)
+		assert.Nil(t, err)
+		assert.Equal(t, i == 0, reservation.Check(request, serverStats, requestConfig) == nil)
+	}
+}
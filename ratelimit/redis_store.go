@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	//DefaultRedisKeyPrefix ...
+	DefaultRedisKeyPrefix = "marathon:ratelimit:"
+)
+
+//gcraScript takes at most n tokens from a GCRA bucket in one round trip. The time of redis is used, so that the
+//clocks of the instances do not matter. The TAT is kept in microseconds and expires when the bucket is full again.
+const gcraScript = `
+redis.replicate_commands()
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tat < now then
+	tat = now
+end
+local taken = n
+if interval > 0 then
+	taken = math.min(n, math.floor((now + interval * (burst - 1) - tat) / interval) + 1)
+end
+if taken <= 0 then
+	return 0
+end
+tat = tat + taken * interval
+redis.call('SET', KEYS[1], tat, 'PX', math.ceil((tat - now) / 1000) + 1)
+return taken
+`
+
+//RedisClient the minimal redis client used by RedisStore, so that any redis library can be adapted to it.
+type RedisClient interface {
+
+	//Eval runs the lua script with the keys and the args, e.g. EVAL script len(keys) keys... args...
+	//It should give up when the context is done.
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+//RedisStore a Store which runs GCRA in redis with a lua script.
+type RedisStore struct {
+	client    RedisClient
+	keyPrefix string
+}
+
+//NewRedisStore ...
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{
+		client:    client,
+		keyPrefix: DefaultRedisKeyPrefix,
+	}
+}
+
+//SetKeyPrefix ...
+func (s *RedisStore) SetKeyPrefix(keyPrefix string) *RedisStore {
+	s.keyPrefix = keyPrefix
+	return s
+}
+
+//Take ...
+func (s *RedisStore) Take(ctx context.Context, key string, n int, emissionInterval time.Duration, burst int) (int, error) {
+	result, err := s.client.Eval(ctx, gcraScript, []string{s.keyPrefix + key},
+		int64(emissionInterval/time.Microsecond), burst, n)
+	if err != nil {
+		return 0, err
+	}
+	switch v := result.(type) {
+	case int64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		return strconv.Atoi(v)
+	case []byte:
+		return strconv.Atoi(string(v))
+	}
+	return 0, fmt.Errorf("unexpected result %v of the rate limit script", result)
+}
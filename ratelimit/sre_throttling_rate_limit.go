@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"math"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nienie/marathon/client"
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/nienie/marathon/logger"
+	"github.com/nienie/marathon/server"
+	"github.com/nienie/marathon/stats"
+)
+
+var (
+	sreThrottleLock sync.RWMutex
+	sreThrottles    map[string]*SREThrottle
+)
+
+func init() {
+	sreThrottles = make(map[string]*SREThrottle)
+	sreThrottleLock = sync.RWMutex{}
+}
+
+//SREThrottle the client side adaptive throttling from the Google SRE book. The requests are dropped locally
+//with the probability max(0, (requests - K*accepts) / (requests + 1)) over a rolling window, so that the
+//rejected requests of an overloaded upstream never hit the network.
+type SREThrottle struct {
+	k        float64
+	requests *stats.RollingCounter
+	accepts  *stats.RollingCounter
+}
+
+//NewSREThrottle a smaller k drops more aggressively, 2 is recommended.
+func NewSREThrottle(k float64, windowSize int) *SREThrottle {
+	if windowSize <= 0 {
+		windowSize = config.DefaultSREThrottlingWindowSize
+	}
+	return &SREThrottle{
+		k:        k,
+		requests: stats.NewRollingCounter(windowSize),
+		accepts:  stats.NewRollingCounter(windowSize),
+	}
+}
+
+//GetRejectProbability ...
+func (t *SREThrottle) GetRejectProbability() float64 {
+	requests := float64(t.requests.Count())
+	accepts := float64(t.accepts.Count())
+	return math.Max(0, (requests-t.k*accepts)/(requests+1))
+}
+
+//Allow counts the request, including the ones dropped locally.
+func (t *SREThrottle) Allow() bool {
+	p := t.GetRejectProbability()
+	t.requests.Inc(1)
+	return p <= 0 || rand.Float64() >= p
+}
+
+//Accept counts a request accepted by the upstream.
+func (t *SREThrottle) Accept() {
+	t.accepts.Inc(1)
+}
+
+//SREThrottlingRateLimit throttles the requests of every client with a SREThrottle.
+type SREThrottlingRateLimit struct{}
+
+//NewSREThrottlingRateLimit ...
+func NewSREThrottlingRateLimit() *SREThrottlingRateLimit {
+	return &SREThrottlingRateLimit{}
+}
+
+//Allow ...
+func (l *SREThrottlingRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
+	throttle := getSREThrottle(requestConfig)
+	if throttle == nil {
+		return true
+	}
+	return throttle.Allow()
+}
+
+//OnComplete ...
+func (l *SREThrottlingRateLimit) OnComplete(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig,
+	response client.Response, latency time.Duration, err error) {
+	throttle := getSREThrottle(requestConfig)
+	if throttle == nil || IsOverloaded(response, err) {
+		return
+	}
+	//rejected by a later rate limit, it never reaches the upstream
+	if clientErr, ok := err.(errors.ClientError); ok && clientErr.GetErrType().IsClientThrottled() {
+		return
+	}
+	throttle.Accept()
+}
+
+//GetSREThrottle nil if it has not been created.
+func GetSREThrottle(clientName string) *SREThrottle {
+	sreThrottleLock.RLock()
+	defer sreThrottleLock.RUnlock()
+	return sreThrottles[clientName]
+}
+
+func getSREThrottle(requestConfig config.ClientConfig) *SREThrottle {
+	if requestConfig == nil ||
+		!requestConfig.GetPropertyAsBool(config.SREThrottlingRateLimitSwitch, config.DefaultSREThrottlingRateLimitSwitch) {
+		return nil
+	}
+
+	key := requestConfig.GetClientName()
+	sreThrottleLock.RLock()
+	throttle, ok := sreThrottles[key]
+	sreThrottleLock.RUnlock()
+	if ok {
+		return throttle
+	}
+	sreThrottleLock.Lock()
+	defer sreThrottleLock.Unlock()
+	if throttle, ok = sreThrottles[key]; ok {
+		return throttle
+	}
+	kStr := requestConfig.GetPropertyAsString(config.SREThrottlingK, config.DefaultSREThrottlingK)
+	k, err := strconv.ParseFloat(kStr, 64)
+	if err != nil || k <= 0 {
+		logger.Warnf(nil, "err_msg=invalid SREThrottlingK||client=%s||k=%s||err=%v", key, kStr, err)
+		k, _ = strconv.ParseFloat(config.DefaultSREThrottlingK, 64)
+	}
+	throttle = NewSREThrottle(k,
+		requestConfig.GetPropertyAsInteger(config.SREThrottlingWindowSize, config.DefaultSREThrottlingWindowSize))
+	sreThrottles[key] = throttle
+	return throttle
+}
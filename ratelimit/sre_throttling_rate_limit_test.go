@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nienie/marathon/config"
+	"github.com/nienie/marathon/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestSREThrottle ...
+func TestSREThrottle(t *testing.T) {
+	throttle := NewSREThrottle(2, 10)
+	for i := 0; i < 100; i++ {
+		assert.True(t, throttle.Allow())
+		throttle.Accept()
+	}
+	assert.Equal(t, float64(0), throttle.GetRejectProbability())
+
+	//the upstream rejects everything, the client starts dropping after requests > 2*accepts
+	dropped := 0
+	for i := 0; i < 1000; i++ {
+		if !throttle.Allow() {
+			dropped++
+		}
+	}
+	assert.True(t, dropped > 0)
+	assert.InDelta(t, (1100.0-200)/1101, throttle.GetRejectProbability(), 0.001)
+}
+
+//TestSREThrottlingRateLimit ...
+func TestSREThrottlingRateLimit(t *testing.T) {
+	sreThrottleLock.Lock()
+	sreThrottles = make(map[string]*SREThrottle)
+	sreThrottleLock.Unlock()
+
+	requestConfig := config.NewDefaultClientConfig("sre", nil)
+	requestConfig.SetProperty(config.SREThrottlingRateLimitSwitch, true)
+	requestConfig.SetProperty(config.SREThrottlingK, "1.5")
+	uri, _ := url.Parse("http://127.0.0.1:8080/sre")
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, Allow(uri, nil, requestConfig))
+		OnComplete(uri, nil, requestConfig, nil, time.Millisecond, nil)
+	}
+	throttle := GetSREThrottle("sre")
+	assert.NotNil(t, throttle)
+	assert.Equal(t, float64(0), throttle.GetRejectProbability())
+
+	//the rejections of the upstream are not accepts
+	for i := 0; i < 10; i++ {
+		Allow(uri, nil, requestConfig)
+		OnComplete(uri, nil, requestConfig, nil, time.Millisecond, errors.NewClientError(errors.ServerThrottled, nil))
+	}
+	assert.InDelta(t, (20-1.5*10)/21.0, throttle.GetRejectProbability(), 0.001)
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	storeLock sync.RWMutex
+	stores    map[string]Store
+)
+
+func init() {
+	stores = make(map[string]Store)
+	storeLock = sync.RWMutex{}
+}
+
+//Store the shared store of the DistributedRateLimit, e.g. redis, the instances of a client which share a quota
+//take the tokens from the same bucket in the store.
+type Store interface {
+
+	//Take takes at most n tokens of the key from a GCRA bucket, which is refilled with one token every
+	//emissionInterval and holds at most burst tokens. It returns the number of tokens taken, and should return
+	//an error when the context is done.
+	Take(ctx context.Context, key string, n int, emissionInterval time.Duration, burst int) (int, error)
+}
+
+//RegisterStore registers the store with the name used by DistributedRateLimitStore.
+func RegisterStore(name string, store Store) {
+	storeLock.Lock()
+	defer storeLock.Unlock()
+	stores[name] = store
+}
+
+//GetStore nil if it has not been registered.
+func GetStore(name string) Store {
+	storeLock.RLock()
+	defer storeLock.RUnlock()
+	return stores[name]
+}
+
+//MemoryStore a Store in the memory of the process, for the tests or a single instance.
+type MemoryStore struct {
+	buckets *bucketCache
+}
+
+//NewMemoryStore ...
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: newBucketCache(),
+	}
+}
+
+//Take ...
+func (s *MemoryStore) Take(ctx context.Context, key string, n int, emissionInterval time.Duration, burst int) (int, error) {
+	bucket, err := s.buckets.getOrCreate(key, func() (interface{}, error) {
+		return NewGCRA(emissionInterval, burst), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return bucket.(*GCRA).TakeAt(time.Now(), n), nil
+}
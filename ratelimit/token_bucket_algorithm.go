@@ -10,18 +10,18 @@ const (
 	DefaultFillInterval = 10 * time.Millisecond
 )
 
-//TokenBucket ...
+//TokenBucket fillCount tokens are put into the bucket every fillInterval, and at most capacity tokens are kept.
+//The tokens are refilled lazily with GCRA when they are taken.
 type TokenBucket struct {
-	ch           chan struct{}
+	gcra         *GCRA
 	capacity     int
 	fillCount    int
 	fillInterval time.Duration
-	stop         chan bool
 }
 
 //NewTokenBucket ...
 func NewTokenBucket(capacity int, fillInterval time.Duration, fillCount int) (*TokenBucket, error) {
-	if capacity <= 0 || fillCount <= 0 {
+	if capacity <= 0 || fillCount <= 0 || fillInterval <= time.Duration(0) {
 		return nil, fmt.Errorf("invalid parameters")
 	}
 
@@ -29,45 +29,26 @@ func NewTokenBucket(capacity int, fillInterval time.Duration, fillCount int) (*T
 		capacity:     capacity,
 		fillInterval: fillInterval,
 		fillCount:    fillCount,
-		ch:           make(chan struct{}, capacity),
-		stop:         make(chan bool, 1),
+		gcra:         NewGCRA(fillInterval/time.Duration(fillCount), capacity),
 	}
-	for i := 0; i < fillCount; i++ {
-		bucket.ch <- struct{}{}
-	}
-	go bucket.run()
 	return bucket, nil
 }
 
-func (b *TokenBucket) run() {
-	t := time.NewTicker(b.fillInterval)
-	defer t.Stop()
-	for {
-		select {
-		case <-b.stop:
-			return
-		case <-t.C:
-			for i := 0; i < b.fillCount; i++ {
-				select {
-				case b.ch <- struct{}{}:
-				default:
-				}
-			}
-		}
-	}
-}
-
 //GetToken ...
 func (b *TokenBucket) GetToken() bool {
-	select {
-	case <-b.ch:
-		return true
-	default:
-		return false
-	}
+	return b.gcra.Allow()
+}
+
+//Reserve takes a token which is available after the delay, ok is false if the delay is longer than maxWait.
+func (b *TokenBucket) Reserve(maxWait time.Duration) (delay time.Duration, ok bool) {
+	return b.gcra.Reserve(maxWait)
+}
+
+//Cancel gives back a token taken by Reserve.
+func (b *TokenBucket) Cancel() {
+	b.gcra.Cancel()
 }
 
-//Stop ...
+//Stop the bucket has no goroutine to stop, it is kept for compatibility.
 func (b *TokenBucket) Stop() {
-	b.stop <- true
 }
@@ -2,20 +2,18 @@ package ratelimit
 
 import (
 	"net/url"
-	"sync"
+	"time"
 
 	"github.com/nienie/marathon/config"
 	"github.com/nienie/marathon/server"
 )
 
 var (
-	tokenBucketLock sync.RWMutex
-	tokenBuckets    map[string]*TokenBucket
+	tokenBuckets *bucketCache
 )
 
 func init() {
-	tokenBuckets = make(map[string]*TokenBucket)
-	tokenBucketLock = sync.RWMutex{}
+	tokenBuckets = newBucketCache()
 }
 
 //TokenBucketRateLimit ...
@@ -28,12 +26,37 @@ func NewTokenBucketRateLimit() *TokenBucketRateLimit {
 
 //Allow ...
 func (l *TokenBucketRateLimit) Allow(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) bool {
-	if uri == nil || requestConfig == nil {
+	bucket := l.getBucket(uri, serverStats, requestConfig)
+	if bucket == nil {
 		return true
 	}
 
+	return bucket.GetToken()
+}
+
+//Reserve ...
+func (l *TokenBucketRateLimit) Reserve(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig,
+	maxWait time.Duration) (time.Duration, func(), bool) {
+	if uri != nil && len(uri.Host) == 0 && serverStats == nil {
+		//every server has its own bucket
+		return 0, nil, true
+	}
+	bucket := l.getBucket(uri, serverStats, requestConfig)
+	if bucket == nil {
+		return 0, func() {}, true
+	}
+
+	delay, ok := bucket.Reserve(maxWait)
+	return delay, bucket.Cancel, ok
+}
+
+func (l *TokenBucketRateLimit) getBucket(uri *url.URL, serverStats *server.Stats, requestConfig config.ClientConfig) *TokenBucket {
+	if uri == nil || requestConfig == nil {
+		return nil
+	}
+
 	if !requestConfig.GetPropertyAsBool(config.TokenBucketRateLimitSwitch, config.DefaultTokenBucketRateLimitSwitch) {
-		return true
+		return nil
 	}
 
 	var key string
@@ -43,34 +66,19 @@ func (l *TokenBucketRateLimit) Allow(uri *url.URL, serverStats *server.Stats, re
 		key = uri.Host + uri.Path
 	}
 
-	bucket := getTokenBucket(key, requestConfig)
-	if bucket == nil {
-		return true
-	}
-
-	return bucket.GetToken()
+	return getTokenBucket(key, requestConfig)
 }
 
 func getTokenBucket(key string, requestConfig config.ClientConfig) *TokenBucket {
-	bucket, ok := tokenBuckets[key]
-	if ok {
-		return bucket
-	}
-	tokenBucketLock.Lock()
-	bucket, ok = tokenBuckets[key]
-	if ok {
-		defer tokenBucketLock.Unlock()
-		return bucket
-	}
-	bucket, err := NewTokenBucket(
-		requestConfig.GetPropertyAsInteger(config.TokenBucketCapacity, config.DefaultTokenBucketCapacity),
-		requestConfig.GetPropertyAsDuration(config.TokenBucketFillInterval, config.DefaultTokenBucketFillInterval),
-		requestConfig.GetPropertyAsInteger(config.TokenBucketFillCount, config.DefaultTokenBucketFillCount),
-	)
+	bucket, err := tokenBuckets.getOrCreate(key, func() (interface{}, error) {
+		return NewTokenBucket(
+			requestConfig.GetPropertyAsInteger(config.TokenBucketCapacity, config.DefaultTokenBucketCapacity),
+			requestConfig.GetPropertyAsDuration(config.TokenBucketFillInterval, config.DefaultTokenBucketFillInterval),
+			requestConfig.GetPropertyAsInteger(config.TokenBucketFillCount, config.DefaultTokenBucketFillCount),
+		)
+	})
 	if err != nil {
 		return nil
 	}
-	tokenBuckets[key] = bucket
-	tokenBucketLock.Unlock()
-	return bucket
+	return bucket.(*TokenBucket)
 }
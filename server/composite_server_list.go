@@ -0,0 +1,213 @@
+package server
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nienie/marathon/config"
+)
+
+//CompositeMode how CompositeServerList combines its sources
+type CompositeMode string
+
+const (
+	//CompositeModeFailover uses the first source, in priority order, that returns a non-empty list.
+	CompositeModeFailover CompositeMode = "failover"
+	//CompositeModeMerge merges the lists of all the sources, duplicated servers are removed.
+	CompositeModeMerge CompositeMode = "merge"
+)
+
+const (
+	//StaticSourceName the name of the source built from ListOfServers of the client config
+	StaticSourceName = "static"
+	//LastKnownSourceName is reported when all the sources returned an empty list and the last known list is kept.
+	LastKnownSourceName = "last-known"
+)
+
+//ListSourceReporter is implemented by the server lists that know which source supplied the current list.
+type ListSourceReporter interface {
+	//GetCurrentSource returns the name of the source that supplied the current list.
+	GetCurrentSource() string
+
+	//IsStale returns true if the current list is not fresh, e.g. an empty result was replaced by the last known list.
+	IsStale() bool
+}
+
+//NamedList a server list source with a name
+type NamedList struct {
+	Name string
+	List List
+}
+
+//CompositeServerList combines several server list sources with priorities, for example service discovery first,
+//DNS second and the static ListOfServers of the config as last resort. An empty result never wipes out the
+//servers, the last known list is kept and flagged stale instead.
+type CompositeServerList struct {
+	mode    CompositeMode
+	sources []*NamedList
+
+	//fetchLock serializes the updates, lock only guards the state below, so the readers are not blocked by the
+	//sources being fetched
+	fetchLock     sync.Mutex
+	lock          sync.RWMutex
+	lastKnown     []*Server
+	lastBySource  map[string][]*Server
+	currentSource string
+	stale         bool
+}
+
+//NewCompositeServerList ...
+func NewCompositeServerList(mode CompositeMode, sources ...*NamedList) *CompositeServerList {
+	if mode != CompositeModeMerge {
+		mode = CompositeModeFailover
+	}
+	l := &CompositeServerList{
+		mode:         mode,
+		sources:      make([]*NamedList, 0, len(sources)),
+		lastBySource: make(map[string][]*Server),
+	}
+	for _, source := range sources {
+		if source != nil && source.List != nil {
+			l.sources = append(l.sources, source)
+		}
+	}
+	return l
+}
+
+//NewCompositeServerListFromConfig creates a CompositeServerList with the mode of ServerListCompositeMode, the static
+//ListOfServers of the config is appended as the last resort source when it is configured.
+func NewCompositeServerListFromConfig(clientConfig config.ClientConfig, sources ...*NamedList) *CompositeServerList {
+	mode := CompositeMode(clientConfig.GetPropertyAsString(config.ServerListCompositeMode, config.DefaultServerListCompositeMode))
+	if len(clientConfig.GetPropertyAsString(config.ListOfServers, config.DefaultListOfServers)) > 0 {
+		sources = append(sources, &NamedList{
+			Name: StaticSourceName,
+			List: NewConfigurationBasedServerList(clientConfig),
+		})
+	}
+	return NewCompositeServerList(mode, sources...)
+}
+
+//GetMode ...
+func (l *CompositeServerList) GetMode() CompositeMode {
+	return l.mode
+}
+
+//GetInitialListOfServers ...
+func (l *CompositeServerList) GetInitialListOfServers() []*Server {
+	return l.combine(func(list List) []*Server {
+		return list.GetInitialListOfServers()
+	})
+}
+
+//GetUpdatedListOfServers ...
+func (l *CompositeServerList) GetUpdatedListOfServers() []*Server {
+	return l.combine(func(list List) []*Server {
+		return list.GetUpdatedListOfServers()
+	})
+}
+
+//GetCurrentSource ...
+func (l *CompositeServerList) GetCurrentSource() string {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.currentSource
+}
+
+//IsStale ...
+func (l *CompositeServerList) IsStale() bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.stale
+}
+
+func (l *CompositeServerList) combine(fetch func(List) []*Server) []*Server {
+	l.fetchLock.Lock()
+	defer l.fetchLock.Unlock()
+	if l.mode == CompositeModeMerge {
+		return l.merge(fetch)
+	}
+	return l.failover(fetch)
+}
+
+func (l *CompositeServerList) failover(fetch func(List) []*Server) []*Server {
+	for _, source := range l.sources {
+		servers := fetch(source.List)
+		if len(servers) == 0 {
+			continue
+		}
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.lastKnown = CloneServerList(servers)
+		l.currentSource = source.Name
+		l.stale = false
+		return servers
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.keepLastKnown()
+}
+
+func (l *CompositeServerList) merge(fetch func(List) []*Server) []*Server {
+	fetched := make([][]*Server, len(l.sources))
+	for i, source := range l.sources {
+		fetched[i] = fetch(source.List)
+	}
+
+	var (
+		merged  = make([]*Server, 0)
+		names   = make([]string, 0, len(l.sources))
+		isStale bool
+	)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for i, source := range l.sources {
+		servers := fetched[i]
+		if len(servers) == 0 {
+			servers = l.lastBySource[source.Name]
+			if len(servers) == 0 {
+				continue
+			}
+			isStale = true
+		} else {
+			l.lastBySource[source.Name] = CloneServerList(servers)
+		}
+		names = append(names, source.Name)
+		merged = appendMissingServers(merged, servers)
+	}
+	if len(merged) == 0 {
+		return l.keepLastKnown()
+	}
+	l.lastKnown = CloneServerList(merged)
+	l.currentSource = strings.Join(names, Delimiter)
+	l.stale = isStale
+	return merged
+}
+
+//keepLastKnown the lock is held.
+func (l *CompositeServerList) keepLastKnown() []*Server {
+	if len(l.lastKnown) == 0 {
+		return nil
+	}
+	l.currentSource = LastKnownSourceName
+	l.stale = true
+	return CloneServerList(l.lastKnown)
+}
+
+func appendMissingServers(servers []*Server, candidates []*Server) []*Server {
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		existed := false
+		for _, svr := range servers {
+			if svr.Equals(candidate) {
+				existed = true
+				break
+			}
+		}
+		if !existed {
+			servers = append(servers, candidate)
+		}
+	}
+	return servers
+}
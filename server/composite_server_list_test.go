@@ -0,0 +1,95 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticList struct {
+	servers []*Server
+}
+
+func (l *staticList) GetInitialListOfServers() []*Server {
+	return l.servers
+}
+
+func (l *staticList) GetUpdatedListOfServers() []*Server {
+	return l.servers
+}
+
+//blockingList blocks the fetch until release is closed, like a slow discovery source.
+type blockingList struct {
+	staticList
+	release chan struct{}
+}
+
+func (l *blockingList) GetUpdatedListOfServers() []*Server {
+	<-l.release
+	return l.servers
+}
+
+//TestCompositeServerListFailover ...
+func TestCompositeServerListFailover(t *testing.T) {
+	primary := &staticList{}
+	secondary := &staticList{}
+	primary.servers, _ = ParseServerListString("http://127.0.0.1:8080")
+	secondary.servers, _ = ParseServerListString("http://127.0.0.2:8080,http://127.0.0.3:8080")
+	l := NewCompositeServerList(CompositeModeFailover, &NamedList{"consul", primary}, &NamedList{"dns", secondary})
+
+	servers := l.GetUpdatedListOfServers()
+	assert.Equal(t, 1, len(servers))
+	assert.Equal(t, "consul", l.GetCurrentSource())
+	assert.False(t, l.IsStale())
+
+	primary.servers = nil
+	servers = l.GetUpdatedListOfServers()
+	assert.Equal(t, 2, len(servers))
+	assert.Equal(t, "dns", l.GetCurrentSource())
+
+	secondary.servers = nil
+	servers = l.GetUpdatedListOfServers()
+	assert.Equal(t, 2, len(servers))
+	assert.Equal(t, LastKnownSourceName, l.GetCurrentSource())
+	assert.True(t, l.IsStale())
+}
+
+//TestCompositeServerListMerge ...
+func TestCompositeServerListMerge(t *testing.T) {
+	first := &staticList{}
+	second := &staticList{}
+	first.servers, _ = ParseServerListString("http://127.0.0.1:8080,http://127.0.0.2:8080")
+	second.servers, _ = ParseServerListString("http://127.0.0.2:8080,http://127.0.0.3:8080")
+	l := NewCompositeServerList(CompositeModeMerge, &NamedList{"consul", first}, &NamedList{"dns", second})
+
+	servers := l.GetUpdatedListOfServers()
+	assert.Equal(t, 3, len(servers))
+	assert.Equal(t, "consul,dns", l.GetCurrentSource())
+	assert.False(t, l.IsStale())
+
+	second.servers = nil
+	servers = l.GetUpdatedListOfServers()
+	assert.Equal(t, 3, len(servers))
+	assert.True(t, l.IsStale())
+}
+
+//TestCompositeServerListSlowSource ...
+func TestCompositeServerListSlowSource(t *testing.T) {
+	slow := &blockingList{release: make(chan struct{})}
+	slow.servers, _ = ParseServerListString("http://127.0.0.1:8080")
+	l := NewCompositeServerList(CompositeModeMerge, &NamedList{"consul", slow})
+
+	done := make(chan []*Server)
+	go func() {
+		done <- l.GetUpdatedListOfServers()
+	}()
+	//the readers are not blocked while the source is fetched
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "", l.GetCurrentSource())
+	assert.False(t, l.IsStale())
+
+	close(slow.release)
+	assert.Equal(t, 1, len(<-done))
+	assert.Equal(t, "consul", l.GetCurrentSource())
+}
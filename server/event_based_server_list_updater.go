@@ -0,0 +1,93 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//DefaultDebounceInterval ...
+	DefaultDebounceInterval = 500 * time.Millisecond
+)
+
+//EventBasedServerListUpdater a ListUpdater that updates the server list when it is notified by
+//the discovery source, instead of polling it. Bursts of notifications arriving within the debounce
+//interval are coalesced into a single update.
+type EventBasedServerListUpdater struct {
+	updaterStatus
+	isActive         int32
+	debounceInterval time.Duration
+	updates          chan struct{}
+	stop             chan bool
+}
+
+//NewEventBasedServerListUpdater ...
+func NewEventBasedServerListUpdater(debounceInterval time.Duration) *EventBasedServerListUpdater {
+	if debounceInterval < 0 {
+		debounceInterval = DefaultDebounceInterval
+	}
+	return &EventBasedServerListUpdater{
+		isActive:         int32(0),
+		debounceInterval: debounceInterval,
+		updates:          make(chan struct{}, 1),
+	}
+}
+
+//Start ...
+func (o *EventBasedServerListUpdater) Start(action UpdateAction) {
+	if atomic.CompareAndSwapInt32(&o.isActive, 0, 1) {
+		o.stop = make(chan bool, 1)
+		go o.run(action, o.stop)
+	}
+}
+
+func (o *EventBasedServerListUpdater) run(action UpdateAction, stop chan bool) {
+	var (
+		debounceTimer *time.Timer
+		fire          <-chan time.Time
+	)
+	for {
+		select {
+		case <-stop:
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			return
+		case <-o.updates:
+			if fire != nil {
+				continue
+			}
+			if o.debounceInterval == 0 {
+				o.doUpdate("EventBasedServerListUpdater", action)
+				continue
+			}
+			debounceTimer = time.NewTimer(o.debounceInterval)
+			fire = debounceTimer.C
+		case <-fire:
+			fire = nil
+			o.doUpdate("EventBasedServerListUpdater", action)
+		}
+	}
+}
+
+//Notify tells the updater that the server list has changed. It never blocks, it is safe
+//to be used as a callback by the discovery source.
+func (o *EventBasedServerListUpdater) Notify() {
+	select {
+	case o.updates <- struct{}{}:
+	default:
+		//an update is already pending
+	}
+}
+
+//UpdateChannel returns the channel that the discovery source can send change notifications to.
+func (o *EventBasedServerListUpdater) UpdateChannel() chan<- struct{} {
+	return o.updates
+}
+
+//Stop ...
+func (o *EventBasedServerListUpdater) Stop() {
+	if atomic.CompareAndSwapInt32(&o.isActive, 1, 0) {
+		o.stop <- true
+	}
+}
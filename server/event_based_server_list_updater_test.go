@@ -0,0 +1,36 @@
+package server
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingUpdateAction struct {
+	count int64
+}
+
+func (a *countingUpdateAction) DoUpdate() {
+	atomic.AddInt64(&a.count, 1)
+}
+
+//TestEventBasedServerListUpdater ...
+func TestEventBasedServerListUpdater(t *testing.T) {
+	action := &countingUpdateAction{}
+	updater := NewEventBasedServerListUpdater(50 * time.Millisecond)
+	updater.Start(action)
+	defer updater.Stop()
+
+	for i := 0; i < 10; i++ {
+		updater.Notify()
+	}
+	updater.UpdateChannel() <- struct{}{}
+	time.Sleep(200 * time.Millisecond)
+
+	assert.Equal(t, int64(1), atomic.LoadInt64(&action.count))
+	assert.Equal(t, int64(1), updater.GetUpdateCount())
+	assert.Nil(t, updater.GetLastError())
+	assert.True(t, updater.GetLastUpdateTime() > 0)
+}
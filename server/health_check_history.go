@@ -0,0 +1,109 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultHealthCheckHistorySize ...
+	DefaultHealthCheckHistorySize = 10
+)
+
+//HealthCheckResult the result of a single health check of a server
+type HealthCheckResult struct {
+	Timestamp time.Time
+	Alive     bool
+	Latency   time.Duration
+}
+
+//HealthCheckHistory keeps the recent health check results of a server and the number of
+//consecutive successes/failures, which are used for the rise/fall thresholds.
+type HealthCheckHistory struct {
+	lock                 sync.RWMutex
+	results              []HealthCheckResult
+	next                 int
+	full                 bool
+	consecutiveSuccesses int
+	consecutiveFailures  int
+}
+
+//NewHealthCheckHistory ...
+func NewHealthCheckHistory(size int) *HealthCheckHistory {
+	if size <= 0 {
+		size = DefaultHealthCheckHistorySize
+	}
+	return &HealthCheckHistory{
+		results: make([]HealthCheckResult, size),
+	}
+}
+
+//Add records a health check result.
+func (h *HealthCheckHistory) Add(result HealthCheckResult) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.results[h.next] = result
+	h.next = (h.next + 1) % len(h.results)
+	if h.next == 0 {
+		h.full = true
+	}
+	if result.Alive {
+		h.consecutiveSuccesses++
+		h.consecutiveFailures = 0
+	} else {
+		h.consecutiveFailures++
+		h.consecutiveSuccesses = 0
+	}
+}
+
+//GetResults returns the recent results, from the oldest to the newest.
+func (h *HealthCheckHistory) GetResults() []HealthCheckResult {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if !h.full {
+		ret := make([]HealthCheckResult, h.next)
+		copy(ret, h.results[:h.next])
+		return ret
+	}
+	ret := make([]HealthCheckResult, 0, len(h.results))
+	ret = append(ret, h.results[h.next:]...)
+	ret = append(ret, h.results[:h.next]...)
+	return ret
+}
+
+//GetLastResult ...
+func (h *HealthCheckHistory) GetLastResult() (HealthCheckResult, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if !h.full && h.next == 0 {
+		return HealthCheckResult{}, false
+	}
+	return h.results[(h.next-1+len(h.results))%len(h.results)], true
+}
+
+//GetAverageLatency returns the average latency of the recent results.
+func (h *HealthCheckHistory) GetAverageLatency() time.Duration {
+	results := h.GetResults()
+	if len(results) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, result := range results {
+		total += result.Latency
+	}
+	return total / time.Duration(len(results))
+}
+
+//GetConsecutiveSuccesses ...
+func (h *HealthCheckHistory) GetConsecutiveSuccesses() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.consecutiveSuccesses
+}
+
+//GetConsecutiveFailures ...
+func (h *HealthCheckHistory) GetConsecutiveFailures() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.consecutiveFailures
+}
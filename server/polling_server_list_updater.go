@@ -9,9 +9,9 @@ import (
 
 //PollingServerListUpdater ...
 type PollingServerListUpdater struct {
+	updaterStatus
 	isActive        int32
 	refreshInterval time.Duration
-	lastUpdatedTime time.Duration
 	refreshTimer    *timer.Timer
 }
 
@@ -20,7 +20,6 @@ func NewPollingServerListUpdater(refreshInterval time.Duration) *PollingServerLi
 	return &PollingServerListUpdater{
 		isActive:        int32(0),
 		refreshInterval: refreshInterval,
-		lastUpdatedTime: time.Duration(0),
 	}
 }
 
@@ -28,13 +27,7 @@ func NewPollingServerListUpdater(refreshInterval time.Duration) *PollingServerLi
 func (o *PollingServerListUpdater) Start(action UpdateAction) {
 	if atomic.CompareAndSwapInt32(&o.isActive, 0, 1) {
 		f := func() {
-			defer func() {
-				if r := recover(); r != nil {
-
-				}
-			}()
-			action.DoUpdate()
-			o.lastUpdatedTime = time.Duration(time.Now().UnixNano())
+			o.doUpdate("PollingServerListUpdater", action)
 		}
 		o.refreshTimer = timer.NewTimer("PollingServerListUpdater")
 		o.refreshTimer.Schedule(f, o.refreshInterval, o.refreshInterval)
@@ -49,8 +42,3 @@ func (o *PollingServerListUpdater) Stop() {
 		}
 	}
 }
-
-//GetLastUpdateTime ...
-func (o *PollingServerListUpdater) GetLastUpdateTime() time.Duration {
-	return o.lastUpdatedTime
-}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -9,29 +12,64 @@ const (
 	ClusterUnknown = "unknown"
 	//DefaultWight ...
 	DefaultWight = 10
+	//DrainMetadataKey the metadata set by the service discovery to drain a server, e.g. drain=true
+	DrainMetadataKey = "drain"
 )
 
 //Server represents a typical server, use Host:Port identifier
 type Server struct {
-	Host        string `json:"host"`
-	Port        int    `json:"port"`
-	Scheme      string `json:"scheme"`
-	IsAliveFlag bool   `json:"is_alive"`
-	TempDown    bool   `json:"-"`
-	Cluster     string `json:"cluster"`
-	Weight      int    `json:"weight"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Scheme  string `json:"scheme"`
+	Cluster string `json:"cluster"`
+	Weight  int    `json:"weight"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	state          int32 //State
+	lastTransition atomic.Value
+}
+
+type serverAlias Server
+
+//MarshalJSON keeps the "is_alive" field of the former format, and adds the state.
+func (s *Server) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		*serverAlias
+		IsAlive bool   `json:"is_alive"`
+		State   string `json:"state"`
+	}{
+		serverAlias: (*serverAlias)(s),
+		IsAlive:     s.IsAlive(),
+		State:       s.GetState().String(),
+	})
+}
+
+//UnmarshalJSON a server which is not alive is restored as down, otherwise its state is unknown.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		*serverAlias
+		IsAlive *bool `json:"is_alive"`
+	}{
+		serverAlias: (*serverAlias)(s),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	if aux.IsAlive != nil && !*aux.IsAlive {
+		s.SetAlive(false)
+	}
+	return nil
 }
 
 //NewServer create a server instance
 func NewServer(scheme string, host string, port int) *Server {
 	return &Server{
-		Scheme:      scheme,
-		Host:        host,
-		Port:        port,
-		IsAliveFlag: true,
-		TempDown:    false,
-		Cluster:     ClusterUnknown,
-		Weight:      DefaultWight,
+		Scheme:  scheme,
+		Host:    host,
+		Port:    port,
+		Cluster: ClusterUnknown,
+		Weight:  DefaultWight,
 	}
 }
 
@@ -70,15 +108,98 @@ func (s *Server) GetScheme() string {
 	return s.Scheme
 }
 
-//SetAlive ...
+//GetState ...
+func (s *Server) GetState() State {
+	return State(atomic.LoadInt32(&s.state))
+}
+
+//GetLastTransition returns the last state change of the server, false if its state has never changed.
+func (s *Server) GetLastTransition() (StateTransition, bool) {
+	if transition, ok := s.lastTransition.Load().(*StateTransition); ok {
+		return *transition, true
+	}
+	return StateTransition{}, false
+}
+
+//Transition moves the server to the state, an invalid transition is rejected with InvalidStateTransitionError.
+//Moving to the current state does nothing.
+func (s *Server) Transition(to State, reason string) error {
+	for {
+		from := s.GetState()
+		if from == to {
+			return nil
+		}
+		if !from.CanTransitionTo(to) {
+			return &InvalidStateTransitionError{From: from, To: to}
+		}
+		if s.compareAndTransition(from, to, reason) {
+			return nil
+		}
+	}
+}
+
+//TransitionFrom moves the server to the state only if it is in one of the given states,
+//it returns whether the state is changed.
+func (s *Server) TransitionFrom(froms []State, to State, reason string) bool {
+	for {
+		from := s.GetState()
+		if from == to || !from.CanTransitionTo(to) {
+			return false
+		}
+		matched := false
+		for _, state := range froms {
+			if state == from {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+		if s.compareAndTransition(from, to, reason) {
+			return true
+		}
+	}
+}
+
+func (s *Server) compareAndTransition(from, to State, reason string) bool {
+	if !atomic.CompareAndSwapInt32(&s.state, int32(from), int32(to)) {
+		return false
+	}
+	s.lastTransition.Store(&StateTransition{
+		From:      from,
+		To:        to,
+		Timestamp: time.Now(),
+		Reason:    reason,
+	})
+	return true
+}
+
+//SetAlive marks the server up or down, an alive server which is out of rotation for other reasons is left alone.
 func (s *Server) SetAlive(isAliveFlag bool) *Server {
-	s.IsAliveFlag = isAliveFlag
+	if isAliveFlag {
+		s.TransitionFrom([]State{StateUnknown, StateWarming, StateDown}, StateUp, "marked alive")
+	} else {
+		s.Transition(StateDown, "marked down")
+	}
+	return s
+}
+
+//InheritState takes over the state of the same server known before, the discovery creates new instances every time.
+func (s *Server) InheritState(known *Server) *Server {
+	if known == nil || known == s {
+		return s
+	}
+	atomic.StoreInt32(&s.state, atomic.LoadInt32(&known.state))
+	if transition, ok := known.lastTransition.Load().(*StateTransition); ok {
+		s.lastTransition.Store(transition)
+	}
 	return s
 }
 
 //IsAlive ...
 func (s *Server) IsAlive() bool {
-	return s.IsAliveFlag
+	return s.GetState().IsAlive()
 }
 
 //Equals ...
@@ -102,13 +223,33 @@ func (s *Server) SetCluster(cluster string) *Server {
 
 //SetTempDown ...
 func (s *Server) SetTempDown(isDown bool) *Server {
-	s.TempDown = isDown
+	if isDown {
+		s.TransitionFrom([]State{StateUnknown, StateUp}, StateTempDown, "marked temp down")
+	} else {
+		s.TransitionFrom([]State{StateTempDown, StateEjected}, StateUp, "marked ready")
+	}
 	return s
 }
 
-//IsTempDown ...
+//IsTempDown whether the server is temporarily out of rotation, including being ejected.
 func (s *Server) IsTempDown() bool {
-	return s.TempDown
+	state := s.GetState()
+	return state == StateTempDown || state == StateEjected
+}
+
+//SetDraining ...
+func (s *Server) SetDraining(isDraining bool) *Server {
+	if isDraining {
+		s.TransitionFrom([]State{StateUnknown, StateUp, StateTempDown, StateEjected}, StateDraining, "drained")
+	} else {
+		s.TransitionFrom([]State{StateDraining}, StateUp, "undrained")
+	}
+	return s
+}
+
+//IsDraining a draining server gets no new requests, but the in-flight ones finish normally.
+func (s *Server) IsDraining() bool {
+	return s.GetState() == StateDraining
 }
 
 //GetWeight ...
@@ -121,3 +262,21 @@ func (s *Server) SetWeight(weight int) *Server {
 	s.Weight = weight
 	return s
 }
+
+//GetMetadata returns the metadata value of key, e.g. the labels from the service discovery.
+func (s *Server) GetMetadata(key string) (string, bool) {
+	if s.Metadata == nil {
+		return "", false
+	}
+	val, ok := s.Metadata[key]
+	return val, ok
+}
+
+//SetMetadata ...
+func (s *Server) SetMetadata(key, val string) *Server {
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]string)
+	}
+	s.Metadata[key] = val
+	return s
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nienie/marathon/config"
+)
+
+//filter types in the ServerListFilters property,
+//e.g. "allow:10.0.0.0/8,deny:10.0.0.5,cluster:bj,excludeCluster:gz,label:zone=a,minServers:2"
+const (
+	filterTypeAllow          = "allow"
+	filterTypeDeny           = "deny"
+	filterTypeCluster        = "cluster"
+	filterTypeExcludeCluster = "excludeCluster"
+	filterTypeLabel          = "label"
+	filterTypeMinServers     = "minServers"
+)
+
+//ChainListFilter applies the filters one by one.
+type ChainListFilter struct {
+	filters []ListFilter
+}
+
+//NewChainListFilter ...
+func NewChainListFilter(filters ...ListFilter) *ChainListFilter {
+	chain := &ChainListFilter{
+		filters: make([]ListFilter, 0, len(filters)),
+	}
+	for _, filter := range filters {
+		chain.Append(filter)
+	}
+	return chain
+}
+
+//Append ...
+func (f *ChainListFilter) Append(filter ListFilter) *ChainListFilter {
+	if filter != nil {
+		f.filters = append(f.filters, filter)
+	}
+	return f
+}
+
+//GetFilteredListOfServers ...
+func (f *ChainListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	for _, filter := range f.filters {
+		servers = filter.GetFilteredListOfServers(servers)
+	}
+	return servers
+}
+
+//MinServersListFilter is a safety net of another filter, if the filtered list has less than minServers servers,
+//the unfiltered list is returned, so that a wrong filter never starves the load balancer.
+type MinServersListFilter struct {
+	filter     ListFilter
+	minServers int
+}
+
+//NewMinServersListFilter ...
+func NewMinServersListFilter(filter ListFilter, minServers int) *MinServersListFilter {
+	return &MinServersListFilter{
+		filter:     filter,
+		minServers: minServers,
+	}
+}
+
+//GetFilteredListOfServers ...
+func (f *MinServersListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	if f.filter == nil {
+		return servers
+	}
+	filtered := f.filter.GetFilteredListOfServers(servers)
+	if len(filtered) < f.minServers && len(filtered) < len(servers) {
+		return servers
+	}
+	return filtered
+}
+
+//RejectAllListFilter filters out every server. It replaces an invalid filter configuration, so that a wrong deny,
+//cluster or label filter does not route to the servers it was meant to exclude.
+type RejectAllListFilter struct{}
+
+//GetFilteredListOfServers ...
+func (f *RejectAllListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	return []*Server{}
+}
+
+//ParseServerListFilters convert a string like "cluster:bj,deny:10.0.0.5" into a ListFilter.
+func ParseServerListFilters(filtersStr string) (ListFilter, error) {
+	var (
+		hostFilter     *HostListFilter
+		clusterFilter  *ClusterListFilter
+		metadataFilter *MetadataListFilter
+		minServers     int
+	)
+	for _, item := range strings.Split(filtersStr, Delimiter) {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		pos := strings.Index(item, ":")
+		if pos <= 0 || pos == len(item)-1 {
+			return nil, fmt.Errorf("invalid server list filter %q", item)
+		}
+		filterType, value := item[:pos], strings.TrimSpace(item[pos+1:])
+		switch filterType {
+		case filterTypeAllow, filterTypeDeny:
+			if hostFilter == nil {
+				hostFilter = NewHostListFilter()
+			}
+			if filterType == filterTypeAllow {
+				hostFilter.Allow(value)
+			} else {
+				hostFilter.Deny(value)
+			}
+		case filterTypeCluster, filterTypeExcludeCluster:
+			if clusterFilter == nil {
+				clusterFilter = NewClusterListFilter()
+			}
+			if filterType == filterTypeCluster {
+				clusterFilter.Include(value)
+			} else {
+				clusterFilter.Exclude(value)
+			}
+		case filterTypeLabel:
+			kv := strings.SplitN(value, "=", 2)
+			if len(kv) != 2 || len(kv[0]) == 0 {
+				return nil, fmt.Errorf("invalid label selector %q", value)
+			}
+			if metadataFilter == nil {
+				metadataFilter = NewMetadataListFilter()
+			}
+			metadataFilter.Select(kv[0], kv[1])
+		case filterTypeMinServers:
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			minServers = int(n)
+		default:
+			return nil, fmt.Errorf("unknown server list filter type %q", filterType)
+		}
+	}
+
+	chain := NewChainListFilter()
+	if hostFilter != nil {
+		chain.Append(hostFilter)
+	}
+	if clusterFilter != nil {
+		chain.Append(clusterFilter)
+	}
+	if metadataFilter != nil {
+		chain.Append(metadataFilter)
+	}
+	if minServers > 0 {
+		return NewMinServersListFilter(chain, minServers), nil
+	}
+	return chain, nil
+}
+
+//NewServerListFilterFromConfig creates the ListFilter from the ServerListFilters property, nil if it is not configured.
+func NewServerListFilterFromConfig(clientConfig config.ClientConfig) (ListFilter, error) {
+	filtersStr := clientConfig.GetPropertyAsString(config.ServerListFilters, config.DefaultServerListFilters)
+	if len(strings.TrimSpace(filtersStr)) == 0 {
+		return nil, nil
+	}
+	return ParseServerListFilters(filtersStr)
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestParseServerListFilters ...
+func TestParseServerListFilters(t *testing.T) {
+	servers, err := ParseServerListString("http://10.0.0.5:80@bj,http://10.0.0.6:80@bj,http://10.1.0.1:80@bj,http://10.0.0.7:80@gz")
+	assert.Nil(t, err)
+	servers[1].SetMetadata("zone", "a")
+
+	filter, err := ParseServerListFilters("cluster:bj,allow:10.0.0.0/16,deny:10.0.0.5")
+	assert.Nil(t, err)
+	filtered := filter.GetFilteredListOfServers(servers)
+	assert.Equal(t, 1, len(filtered))
+	assert.Equal(t, "10.0.0.6:80", filtered[0].GetHostPort())
+
+	filter, err = ParseServerListFilters("excludeCluster:bj,label:zone=a,minServers:1")
+	assert.Nil(t, err)
+	filtered = filter.GetFilteredListOfServers(servers)
+	assert.Equal(t, 4, len(filtered))
+
+	filter, err = ParseServerListFilters("label:zone=a")
+	assert.Nil(t, err)
+	filtered = filter.GetFilteredListOfServers(servers)
+	assert.Equal(t, 1, len(filtered))
+
+	_, err = ParseServerListFilters("unknown:1")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"net"
+)
+
+//HostListFilter filters the servers by host allowlist and denylist, an entry is either a host or a CIDR.
+//An empty allowlist allows all the hosts, the denylist always wins.
+type HostListFilter struct {
+	allowHosts map[string]bool
+	allowNets  []*net.IPNet
+	denyHosts  map[string]bool
+	denyNets   []*net.IPNet
+}
+
+//NewHostListFilter ...
+func NewHostListFilter() *HostListFilter {
+	return &HostListFilter{
+		allowHosts: make(map[string]bool),
+		allowNets:  make([]*net.IPNet, 0),
+		denyHosts:  make(map[string]bool),
+		denyNets:   make([]*net.IPNet, 0),
+	}
+}
+
+//Allow adds a host or a CIDR into the allowlist.
+func (f *HostListFilter) Allow(hostOrCIDR string) *HostListFilter {
+	if _, ipNet, err := net.ParseCIDR(hostOrCIDR); err == nil {
+		f.allowNets = append(f.allowNets, ipNet)
+	} else {
+		f.allowHosts[hostOrCIDR] = true
+	}
+	return f
+}
+
+//Deny adds a host or a CIDR into the denylist.
+func (f *HostListFilter) Deny(hostOrCIDR string) *HostListFilter {
+	if _, ipNet, err := net.ParseCIDR(hostOrCIDR); err == nil {
+		f.denyNets = append(f.denyNets, ipNet)
+	} else {
+		f.denyHosts[hostOrCIDR] = true
+	}
+	return f
+}
+
+func matchHost(svr *Server, hosts map[string]bool, nets []*net.IPNet) bool {
+	if hosts[svr.GetHost()] || hosts[svr.GetHostPort()] {
+		return true
+	}
+	ip := net.ParseIP(svr.GetHost())
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+//GetFilteredListOfServers ...
+func (f *HostListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	hasAllowList := len(f.allowHosts) > 0 || len(f.allowNets) > 0
+	ret := make([]*Server, 0, len(servers))
+	for _, svr := range servers {
+		if matchHost(svr, f.denyHosts, f.denyNets) {
+			continue
+		}
+		if hasAllowList && !matchHost(svr, f.allowHosts, f.allowNets) {
+			continue
+		}
+		ret = append(ret, svr)
+	}
+	return ret
+}
+
+//ClusterListFilter filters the servers by their clusters.
+//An empty include set includes all the clusters, the exclude set always wins.
+type ClusterListFilter struct {
+	includeClusters map[string]bool
+	excludeClusters map[string]bool
+}
+
+//NewClusterListFilter ...
+func NewClusterListFilter() *ClusterListFilter {
+	return &ClusterListFilter{
+		includeClusters: make(map[string]bool),
+		excludeClusters: make(map[string]bool),
+	}
+}
+
+//Include pins the servers to the cluster.
+func (f *ClusterListFilter) Include(cluster string) *ClusterListFilter {
+	f.includeClusters[cluster] = true
+	return f
+}
+
+//Exclude ...
+func (f *ClusterListFilter) Exclude(cluster string) *ClusterListFilter {
+	f.excludeClusters[cluster] = true
+	return f
+}
+
+//GetFilteredListOfServers ...
+func (f *ClusterListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	ret := make([]*Server, 0, len(servers))
+	for _, svr := range servers {
+		cluster := svr.GetCluster()
+		if f.excludeClusters[cluster] {
+			continue
+		}
+		if len(f.includeClusters) > 0 && !f.includeClusters[cluster] {
+			continue
+		}
+		ret = append(ret, svr)
+	}
+	return ret
+}
+
+//MetadataListFilter selects the servers by metadata labels. A server is selected when it matches all the
+//label keys, the values of the same key are ORed.
+type MetadataListFilter struct {
+	selectors map[string]map[string]bool
+}
+
+//NewMetadataListFilter ...
+func NewMetadataListFilter() *MetadataListFilter {
+	return &MetadataListFilter{
+		selectors: make(map[string]map[string]bool),
+	}
+}
+
+//Select adds a label selector key=value.
+func (f *MetadataListFilter) Select(key, value string) *MetadataListFilter {
+	values, ok := f.selectors[key]
+	if !ok {
+		values = make(map[string]bool)
+		f.selectors[key] = values
+	}
+	values[value] = true
+	return f
+}
+
+//GetFilteredListOfServers ...
+func (f *MetadataListFilter) GetFilteredListOfServers(servers []*Server) []*Server {
+	ret := make([]*Server, 0, len(servers))
+	for _, svr := range servers {
+		matched := true
+		for key, values := range f.selectors {
+			val, ok := svr.GetMetadata(key)
+			if !ok || !values[val] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ret = append(ret, svr)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//ListSnapshot the last known server list persisted on the local disk
+type ListSnapshot struct {
+	Timestamp int64     `json:"timestamp"` //unix timestamp, unit: seconds
+	Servers   []*Server `json:"servers"`
+}
+
+//ListSnapshotStore saves and loads the snapshot of a server list to/from a local file, so that a process
+//can start with the last known servers when the discovery backend is down.
+type ListSnapshotStore struct {
+	path string
+	lock sync.Mutex
+}
+
+//NewListSnapshotStore ...
+func NewListSnapshotStore(path string) *ListSnapshotStore {
+	return &ListSnapshotStore{
+		path: path,
+	}
+}
+
+//GetPath ...
+func (s *ListSnapshotStore) GetPath() string {
+	return s.path
+}
+
+//Save writes the servers, with their health state and weights, into the snapshot file.
+//The file is replaced atomically, a reader never sees a partial snapshot.
+func (s *ListSnapshotStore) Save(servers []*Server) error {
+	snapshot := &ListSnapshot{
+		Timestamp: time.Now().Unix(),
+		Servers:   servers,
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), s.path)
+}
+
+//Load reads the servers from the snapshot file, the snapshot older than maxAge is rejected.
+//maxAge <= 0 means the snapshot never expires. A missing file, e.g. on the first start, is an empty snapshot.
+func (s *ListSnapshotStore) Load(maxAge time.Duration) ([]*Server, error) {
+	s.lock.Lock()
+	data, err := ioutil.ReadFile(s.path)
+	s.lock.Unlock()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ListSnapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	age := time.Since(time.Unix(snapshot.Timestamp, 0))
+	if maxAge > 0 && age > maxAge {
+		return nil, fmt.Errorf("server list snapshot %s is expired, age=%v, max_age=%v", s.path, age, maxAge)
+	}
+
+	servers := make([]*Server, 0, len(snapshot.Servers))
+	for _, svr := range snapshot.Servers {
+		if svr == nil || len(svr.Host) == 0 {
+			continue
+		}
+		svr.SetCluster(svr.Cluster)
+		servers = append(servers, svr)
+	}
+	return servers, nil
+}
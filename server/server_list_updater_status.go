@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nienie/marathon/logger"
+)
+
+//updaterStatus records the outcome of the server list updates, it is shared by the ListUpdater implementations.
+type updaterStatus struct {
+	lastUpdatedTime int64 //nanoseconds
+	updateCount     int64
+	lastError       atomic.Value //the panic of the last update
+}
+
+//doUpdate runs the update action, a panic in the action is recovered and recorded as the last error.
+func (o *updaterStatus) doUpdate(name string, action UpdateAction) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := fmt.Errorf("%s update action paniced: %v", name, r)
+			o.lastError.Store(&err)
+			logger.Warnf(nil, "err_msg=%s", err.Error())
+		}
+	}()
+	action.DoUpdate()
+	var noError error
+	o.lastError.Store(&noError)
+	atomic.StoreInt64(&o.lastUpdatedTime, time.Now().UnixNano())
+	atomic.AddInt64(&o.updateCount, int64(1))
+}
+
+//GetLastUpdateTime ...
+func (o *updaterStatus) GetLastUpdateTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&o.lastUpdatedTime))
+}
+
+//GetUpdateCount returns the number of successful updates.
+func (o *updaterStatus) GetUpdateCount() int64 {
+	return atomic.LoadInt64(&o.updateCount)
+}
+
+//GetLastError returns the panic of the last update as an error, nil if it did not panic. UpdateAction.DoUpdate
+//returns no error, so a discovery failure, e.g. an empty list from a source which is down, is not recorded here,
+//see ListSourceReporter.IsStale and DynamicServerListLoadBalancer.IsServerListStale for that.
+func (o *updaterStatus) GetLastError() error {
+	if err, ok := o.lastError.Load().(*error); ok {
+		return *err
+	}
+	return nil
+}
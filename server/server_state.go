@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+//State the state of a server in the load balancer, it is changed atomically with validated transitions.
+type State int32
+
+const (
+	//StateUnknown the server has not been checked yet, it is considered alive.
+	StateUnknown State = iota
+	//StateWarming the down server passes the health check, but not enough times to be up again.
+	StateWarming
+	//StateUp the server is healthy and in rotation.
+	StateUp
+	//StateTempDown the server is taken out of rotation temporarily.
+	StateTempDown
+	//StateEjected the server is taken out of rotation because its circuit breaker is tripped.
+	StateEjected
+	//StateDraining the server gets no new requests, but the in-flight ones finish normally.
+	StateDraining
+	//StateDown the server fails the health check.
+	StateDown
+)
+
+var stateNames = map[State]string{
+	StateUnknown:  "Unknown",
+	StateWarming:  "Warming",
+	StateUp:       "Up",
+	StateTempDown: "TempDown",
+	StateEjected:  "Ejected",
+	StateDraining: "Draining",
+	StateDown:     "Down",
+}
+
+//validTransitions the states a server may move to from every state.
+var validTransitions = map[State][]State{
+	StateUnknown:  {StateWarming, StateUp, StateTempDown, StateEjected, StateDraining, StateDown},
+	StateWarming:  {StateUp, StateDraining, StateDown},
+	StateUp:       {StateTempDown, StateEjected, StateDraining, StateDown},
+	StateTempDown: {StateUp, StateEjected, StateDraining, StateDown},
+	StateEjected:  {StateUp, StateDraining, StateDown},
+	StateDraining: {StateUp, StateDown},
+	StateDown:     {StateWarming, StateUp, StateDraining},
+}
+
+//String ...
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+//IsAlive whether the server passes the health check.
+func (s State) IsAlive() bool {
+	switch s {
+	case StateWarming, StateDown:
+		return false
+	}
+	return true
+}
+
+//IsAvailable whether the server may be chosen for new requests, the rules consult this only.
+func (s State) IsAvailable() bool {
+	return s == StateUp || s == StateUnknown
+}
+
+//CanTransitionTo ...
+func (s State) CanTransitionTo(to State) bool {
+	for _, state := range validTransitions[s] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+//StateTransition records a state change of a server.
+type StateTransition struct {
+	From      State
+	To        State
+	Timestamp time.Time
+	Reason    string
+}
+
+//InvalidStateTransitionError ...
+type InvalidStateTransitionError struct {
+	From State
+	To   State
+}
+
+//Error ...
+func (e *InvalidStateTransitionError) Error() string {
+	return fmt.Sprintf("invalid server state transition from %s to %s", e.From, e.To)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestServerStateTransition ...
+func TestServerStateTransition(t *testing.T) {
+	svr := NewServer("http", "127.0.0.1", 8080)
+	assert.Equal(t, StateUnknown, svr.GetState())
+	assert.True(t, svr.IsAlive())
+	_, ok := svr.GetLastTransition()
+	assert.False(t, ok)
+
+	assert.Nil(t, svr.Transition(StateDown, "health check failed"))
+	assert.False(t, svr.IsAlive())
+	transition, ok := svr.GetLastTransition()
+	assert.True(t, ok)
+	assert.Equal(t, StateUnknown, transition.From)
+	assert.Equal(t, StateDown, transition.To)
+	assert.Equal(t, "health check failed", transition.Reason)
+	assert.False(t, transition.Timestamp.IsZero())
+
+	err := svr.Transition(StateEjected, "circuit breaker tripped")
+	assert.NotNil(t, err)
+	assert.IsType(t, &InvalidStateTransitionError{}, err)
+	assert.Equal(t, StateDown, svr.GetState())
+
+	assert.Nil(t, svr.Transition(StateWarming, "health check passed"))
+	assert.False(t, svr.GetState().IsAvailable())
+	assert.Nil(t, svr.Transition(StateUp, "health check passed"))
+	assert.True(t, svr.GetState().IsAvailable())
+
+	svr.SetTempDown(true)
+	assert.True(t, svr.IsTempDown())
+	assert.True(t, svr.IsAlive())
+	assert.False(t, svr.GetState().IsAvailable())
+	svr.SetTempDown(false)
+	assert.Equal(t, StateUp, svr.GetState())
+
+	svr.SetDraining(true)
+	assert.True(t, svr.IsDraining())
+	assert.False(t, svr.TransitionFrom([]State{StateUp}, StateTempDown, "marked temp down"))
+	svr.SetAlive(false)
+	assert.Equal(t, StateDown, svr.GetState())
+}
+
+//TestServerStateConcurrent ...
+func TestServerStateConcurrent(t *testing.T) {
+	svr := NewServer("http", "127.0.0.1", 8080)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				svr.SetAlive(i%2 == 0)
+				svr.SetTempDown(j%2 == 0)
+				svr.GetState().IsAvailable()
+			}
+		}(i)
+	}
+	wg.Wait()
+	_, ok := stateNames[svr.GetState()]
+	assert.True(t, ok)
+}
+
+//TestServerJSON ...
+func TestServerJSON(t *testing.T) {
+	svr := NewServer("http", "127.0.0.1", 8080)
+	svr.SetAlive(false)
+	data, err := json.Marshal(svr)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"is_alive":false`)
+	assert.Contains(t, string(data), `"state":"Down"`)
+
+	loaded := &Server{}
+	assert.Nil(t, json.Unmarshal(data, loaded))
+	assert.Equal(t, "127.0.0.1:8080", loaded.GetHostPort())
+	assert.Equal(t, StateDown, loaded.GetState())
+}
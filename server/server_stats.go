@@ -46,6 +46,7 @@ type Stats struct {
 
 	RequestCountsSlidingWindowSize int
 	ResponseTimeWindowSize         int
+	HealthCheckHistorySize         int
 
 	//for stats
 	totalRequests                     metrics.Counter
@@ -66,6 +67,8 @@ type Stats struct {
 
 	serverFailureCounts  *stats.RollingCounter //server failure counts in a sliding window time
 	requestCountInWindow *stats.RollingCounter //request count in a window time
+
+	healthCheckHistory *HealthCheckHistory
 }
 
 //NewDefaultServerStats ...
@@ -78,6 +81,7 @@ func NewDefaultServerStats() *Stats {
 
 		RequestCountsSlidingWindowSize: DefaultRequestCountsSlidingWindowSize,
 		ResponseTimeWindowSize:         DefaultResponseTimeWindowSize,
+		HealthCheckHistorySize:         DefaultHealthCheckHistorySize,
 
 		responseTimeDist: stats.NewDistribution(),
 
@@ -95,11 +99,17 @@ func (o *Stats) Initialize(svr *Server) {
 	o.serverFailureCounts = stats.NewRollingCounter(o.RequestCountsSlidingWindowSize)
 	o.requestCountInWindow = stats.NewRollingCounter(o.RequestCountsSlidingWindowSize)
 	o.responseTimeInWindow = stats.NewRollingSample(o.ResponseTimeWindowSize)
+	o.healthCheckHistory = NewHealthCheckHistory(o.HealthCheckHistorySize)
 }
 
 //Close ...
 func (o *Stats) Close() {}
 
+//GetHealthCheckHistory returns the recent health check results of the server.
+func (o *Stats) GetHealthCheckHistory() *HealthCheckHistory {
+	return o.healthCheckHistory
+}
+
 //AddToFailureCount increment the count of failure for this server
 func (o *Stats) AddToFailureCount() {
 	o.serverFailureCounts.Inc(int64(1))
@@ -146,7 +156,7 @@ func (o *Stats) DecrementActiveRequestsCount() {
 func (o *Stats) GetActiveRequestsCount(currentTime time.Duration) int64 {
 	count := o.activeRequestsCount.Count()
 
-	if currentTime-time.Duration(o.lastActiveRequestsCountChangeTimestamp) > o.ActiveRequestsCountTimeout || count < 0 {
+	if currentTime-time.Duration(atomic.LoadInt64(&o.lastActiveRequestsCountChangeTimestamp)) > o.ActiveRequestsCountTimeout || count < 0 {
 		o.activeRequestsCount.Clear()
 		return 0
 	}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServers(count int) []*Server {
+	servers := make([]*Server, 0, count)
+	for i := 0; i < count; i++ {
+		servers = append(servers, NewServer("http", fmt.Sprintf("10.0.%d.%d", i/256, i%256), 8080))
+	}
+	return servers
+}
+
+func countCommonServers(a, b []*Server) int {
+	common := 0
+	for _, x := range a {
+		for _, y := range b {
+			if x.GetHostPort() == y.GetHostPort() {
+				common++
+			}
+		}
+	}
+	return common
+}
+
+//TestSubsetListFilter ...
+func TestSubsetListFilter(t *testing.T) {
+	servers := newTestServers(100)
+
+	//the servers are spread over the clients around the mean of 100, binomially
+	hits := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		subset := NewSubsetListFilter(strconv.Itoa(i), 10).GetFilteredListOfServers(servers)
+		assert.Equal(t, 10, len(subset))
+		for _, svr := range subset {
+			hits[svr.GetHostPort()]++
+		}
+	}
+	assert.Equal(t, 100, len(hits))
+	for _, count := range hits {
+		assert.True(t, count >= 70 && count <= 130)
+	}
+
+	//removing or adding a server changes at most one server of the subset, even if the number of subsets changes
+	for _, clientID := range []string{"3", "9", "15", "42", "client-a"} {
+		filter := NewSubsetListFilter(clientID, 10)
+		before := filter.GetFilteredListOfServers(servers)
+		assert.True(t, countCommonServers(before, filter.GetFilteredListOfServers(servers[:99])) >= 9)
+		assert.True(t, countCommonServers(before, filter.GetFilteredListOfServers(newTestServers(101))) >= 9)
+		assert.True(t, countCommonServers(before,
+			filter.GetFilteredListOfServers(append(CloneServerList(servers[:50]), servers[51:]...))) >= 9)
+	}
+
+	assert.Equal(t, 5, len(NewSubsetListFilter("client-a", 10).GetFilteredListOfServers(servers[:5])))
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/nienie/marathon/config"
+)
+
+//NewTLSConfig creates the tls.Config from the TLS settings of the client config,
+//nil if nothing is configured, which means the default TLS settings are used.
+func NewTLSConfig(clientConfig config.ClientConfig) (*tls.Config, error) {
+	if clientConfig == nil {
+		return nil, nil
+	}
+	insecureSkipVerify := clientConfig.GetPropertyAsBool(config.TLSInsecureSkipVerify, config.DefaultTLSInsecureSkipVerify)
+	serverName := clientConfig.GetPropertyAsString(config.TLSServerName, config.DefaultTLSServerName)
+	caFile := clientConfig.GetPropertyAsString(config.TLSCAFile, config.DefaultTLSCAFile)
+	if !insecureSkipVerify && len(serverName) == 0 && len(caFile) == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+		ServerName:         serverName,
+	}
+	if len(caFile) > 0 {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/nienie/marathon/config"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestNewTLSConfig ...
+func TestNewTLSConfig(t *testing.T) {
+	tlsConfig, err := NewTLSConfig(config.NewDefaultClientConfig("tls", nil))
+	assert.Nil(t, err)
+	assert.Nil(t, tlsConfig)
+
+	clientConfig := config.NewDefaultClientConfig("tls", nil)
+	clientConfig.SetProperty(config.TLSInsecureSkipVerify, true)
+	clientConfig.SetProperty(config.TLSServerName, "example.com")
+	tlsConfig, err = NewTLSConfig(clientConfig)
+	assert.Nil(t, err)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Equal(t, "example.com", tlsConfig.ServerName)
+	assert.Nil(t, tlsConfig.RootCAs)
+
+	svr := httptest.NewTLSServer(nil)
+	defer svr.Close()
+	caFile, err := ioutil.TempFile("", "ca")
+	assert.Nil(t, err)
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	caFile.Close()
+
+	clientConfig = config.NewDefaultClientConfig("tls", nil)
+	clientConfig.SetProperty(config.TLSCAFile, caFile.Name())
+	tlsConfig, err = NewTLSConfig(clientConfig)
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+
+	//the file does not exist or has no certificate
+	clientConfig.SetProperty(config.TLSCAFile, caFile.Name()+".missing")
+	_, err = NewTLSConfig(clientConfig)
+	assert.NotNil(t, err)
+	empty, _ := ioutil.TempFile("", "ca")
+	empty.Close()
+	defer os.Remove(empty.Name())
+	clientConfig.SetProperty(config.TLSCAFile, empty.Name())
+	_, err = NewTLSConfig(clientConfig)
+	assert.NotNil(t, err)
+}